@@ -0,0 +1,223 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+)
+
+// newFlags returns a flag set for a subcommand, reporting errors as
+// errUsage only.
+func newFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	return flags
+}
+
+// parse parses the subcommand flags, requiring exactly nargs positional
+// arguments, or at least one when nargs is negative.
+func parse(flags *flag.FlagSet, args []string, nargs int) error {
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if nargs < 0 && flags.NArg() <= 0 || nargs >= 0 && flags.NArg() != nargs {
+		return errUsage
+	}
+	return nil
+}
+
+func deviceRegister(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("device register")
+	var device pushwoosh.Device
+	flags.StringVar(&device.HardwareId, "hwid", "", "")
+	flags.StringVar(&device.PushToken, "token", "", "")
+	flags.StringVar(&device.Language, "language", "", "")
+	flags.IntVar(&device.TimeZone, "timezone", 0, "")
+	deviceType := flags.String("type", "", "")
+	if err := parse(flags, args, 0); err != nil {
+		return nil, err
+	}
+	t, err := pushwoosh.ParseDeviceType(*deviceType)
+	if err != nil {
+		return nil, err
+	}
+	device.Type = t
+	return client.Devices.Register(device)
+}
+
+func deviceUnregister(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("device unregister")
+	hwid := flags.String("hwid", "", "")
+	if err := parse(flags, args, 0); err != nil {
+		return nil, err
+	}
+	return client.Devices.Unregister(*hwid)
+}
+
+// taggedDevice is a device whose tags come from the command line.
+type taggedDevice struct {
+	pushwoosh.Device
+	tags map[string]interface{}
+}
+
+func (d taggedDevice) DeviceTags() map[string]interface{} {
+	return d.tags
+}
+
+func deviceTagsSet(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("device tags set")
+	device := taggedDevice{tags: map[string]interface{}{}}
+	flags.StringVar(&device.HardwareId, "hwid", "", "")
+	if err := parse(flags, args, -1); err != nil {
+		return nil, err
+	}
+	for _, arg := range flags.Args() {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, errUsage
+		}
+		device.tags[arg[:i]] = parseTagValue(arg[i+1:])
+	}
+	return client.Devices.SetTags(device)
+}
+
+// parseTagValue decodes a tag value as JSON, or takes it as a string.
+func parseTagValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
+
+func deviceBadgeSet(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("device badge set")
+	hwid := flags.String("hwid", "", "")
+	if err := parse(flags, args, 1); err != nil {
+		return nil, err
+	}
+	badge, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return nil, errUsage
+	}
+	return client.Devices.SetBadge(pushwoosh.Device{HardwareId: *hwid}, badge)
+}
+
+func deviceZoneNearest(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("device zone nearest")
+	hwid := flags.String("hwid", "", "")
+	lat := flags.Float64("lat", 0, "")
+	lng := flags.Float64("lng", 0, "")
+	if err := parse(flags, args, 0); err != nil {
+		return nil, err
+	}
+	return client.Devices.NearestZone(pushwoosh.Device{HardwareId: *hwid}, *lat, *lng)
+}
+
+func deviceImport(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("device import")
+	mappingPath := flags.String("mapping", "", "")
+	format := flags.String("format", "csv", "")
+	concurrency := flags.Int("concurrency", pushwoosh.DefaultImportConcurrency, "")
+	statePath := flags.String("state", "", "")
+	if err := parse(flags, args, 1); err != nil {
+		return nil, err
+	}
+	opts := pushwoosh.ImportOptions{Concurrency: *concurrency, StatePath: *statePath}
+	switch *format {
+	case "csv":
+		opts.Format = pushwoosh.ExportCSV
+	case "jsonl":
+		opts.Format = pushwoosh.ExportJSON
+	default:
+		return nil, errUsage
+	}
+	mapping, err := pushwoosh.LoadImportMapping(*mappingPath)
+	if err != nil {
+		return nil, err
+	}
+	opts.Mapping = mapping
+
+	var r io.Reader = os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	// Stop on interrupt, saving the progress so far.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return client.Devices.Import(ctx, r, opts)
+}
+
+func messageSend(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("message send")
+	var n pushwoosh.Notification
+	content := flags.String("content", "", "")
+	flags.StringVar(&n.Preset, "preset", "", "")
+	flags.StringVar(&n.SendDate, "send-date", "", "")
+	devices := flags.String("devices", "", "")
+	platforms := flags.String("platforms", "", "")
+	if err := parse(flags, args, 0); err != nil {
+		return nil, err
+	}
+	if len(*content) > 0 {
+		n.Content = pushwoosh.Text(*content)
+	}
+	if len(*devices) > 0 {
+		n.Devices = strings.Split(*devices, ",")
+	}
+	if len(*platforms) > 0 {
+		for _, name := range strings.Split(*platforms, ",") {
+			t, err := pushwoosh.ParseDeviceType(name)
+			if err != nil {
+				return nil, err
+			}
+			n.Platforms = append(n.Platforms, t)
+		}
+	}
+	return client.Messages.Create(n)
+}
+
+func messageDelete(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("message delete")
+	if err := parse(flags, args, 1); err != nil {
+		return nil, err
+	}
+	return client.Messages.Delete(flags.Arg(0))
+}
+
+func messageStats(client *pushwoosh.Client, args []string) (interface{}, error) {
+	flags := newFlags("message stats")
+	if err := parse(flags, args, 1); err != nil {
+		return nil, err
+	}
+	return client.Messages.Stats(flags.Arg(0))
+}
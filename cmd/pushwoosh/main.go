@@ -0,0 +1,205 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+// Command pushwoosh calls the Pushwoosh API from the command line.
+//
+// Usage:
+//
+//	pushwoosh [flags] device register -hwid ID -token TOKEN -type TYPE [-language LANG] [-timezone OFFSET]
+//	pushwoosh [flags] device unregister -hwid ID
+//	pushwoosh [flags] device tags set -hwid ID NAME=VALUE...
+//	pushwoosh [flags] device badge set -hwid ID BADGE
+//	pushwoosh [flags] device zone nearest -hwid ID -lat LAT -lng LNG
+//	pushwoosh [flags] device import -mapping PATH [-format csv|jsonl] [-concurrency N] [-state PATH] FILE
+//	pushwoosh [flags] message send -content TEXT [-devices ID,...] [-platforms TYPE,...] [-send-date DATE]
+//	pushwoosh [flags] message delete CODE
+//	pushwoosh [flags] message stats CODE
+//
+// The flags are:
+//
+//	-app CODE       application code
+//	-config PATH    configuration file, by default $PUSHWOOSH_CONFIG or ~/.pushwoosh.json
+//	-output FORMAT  "json", the default, or "table"
+//
+// Settings are taken from the flags, then from the PUSHWOOSH_APPLICATION,
+// PUSHWOOSH_AUTH_TOKEN and PUSHWOOSH_BASE_URL environment variables, then
+// from the configuration file:
+//
+//	{"application": "XXXXX-XXXXX", "auth_token": "...", "base_url": "..."}
+//
+// Tag values are parsed as JSON when possible, so count=3 sets a number and
+// name=foo a string.
+//
+// The import command registers the devices of a file, "-" for the standard
+// input, mapping its columns to device fields and tags as told by the
+// mapping file, see pushwoosh.ImportMapping:
+//
+//	{"hwid": "id", "push_token": "token", "device_type": "platform",
+//	 "tags": {"country": "Country"}}
+//
+// With -state, the progress is saved to the given file and an interrupted
+// import run again resumes where it stopped. The result lists the records
+// rejected.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+)
+
+// errUsage is returned for invalid command lines.
+var errUsage = errors.New("invalid usage, see go doc github.com/stelapps/go-pushwoosh/cmd/pushwoosh")
+
+func main() {
+	err := run(os.Args[1:], os.Getenv, os.Stdout)
+	if err == errUsage {
+		fmt.Fprintln(os.Stderr, "pushwoosh:", err)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pushwoosh:", err)
+		os.Exit(1)
+	}
+}
+
+// config holds the settings of a run.
+type config struct {
+	Application string `json:"application"`
+	AuthToken   string `json:"auth_token"`
+	BaseURL     string `json:"base_url"`
+}
+
+// command runs a subcommand, returning the response to print.
+type command func(client *pushwoosh.Client, args []string) (interface{}, error)
+
+var commands = map[string]command{
+	"device register":     deviceRegister,
+	"device unregister":   deviceUnregister,
+	"device tags set":     deviceTagsSet,
+	"device badge set":    deviceBadgeSet,
+	"device zone nearest": deviceZoneNearest,
+	"device import":       deviceImport,
+	"message send":        messageSend,
+	"message delete":      messageDelete,
+	"message stats":       messageStats,
+}
+
+func run(args []string, getenv func(string) string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("pushwoosh", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	app := flags.String("app", "", "")
+	configPath := flags.String("config", "", "")
+	output := flags.String("output", "json", "")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if *output != "json" && *output != "table" {
+		return errUsage
+	}
+
+	cmd, args := lookupCommand(flags.Args())
+	if cmd == nil {
+		return errUsage
+	}
+
+	cfg, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		return err
+	}
+	if len(*app) > 0 {
+		cfg.Application = *app
+	}
+	client, err := newClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := cmd(client, args)
+	if err != nil {
+		return err
+	}
+	if *output == "table" {
+		return writeTable(stdout, resp)
+	}
+	return writeJSON(stdout, resp)
+}
+
+// lookupCommand finds the longest command prefixing args.
+func lookupCommand(args []string) (command, []string) {
+	for n := 3; n > 0; n-- {
+		if len(args) < n {
+			continue
+		}
+		name := args[0]
+		for _, arg := range args[1:n] {
+			name += " " + arg
+		}
+		if cmd, ok := commands[name]; ok {
+			return cmd, args[n:]
+		}
+	}
+	return nil, nil
+}
+
+// loadConfig reads the configuration file, if any, and overrides it with
+// the environment.
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	var cfg config
+	explicit := len(path) > 0
+	if !explicit {
+		if path = getenv("PUSHWOOSH_CONFIG"); len(path) > 0 {
+			explicit = true
+		} else if home := getenv("HOME"); len(home) > 0 {
+			path = filepath.Join(home, ".pushwoosh.json")
+		}
+	}
+	if len(path) > 0 {
+		b, err := ioutil.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(b, &cfg); err != nil {
+				return cfg, fmt.Errorf("Invalid configuration file %s: %s", path, err)
+			}
+		case explicit || !os.IsNotExist(err):
+			return cfg, err
+		}
+	}
+
+	if v := getenv("PUSHWOOSH_APPLICATION"); len(v) > 0 {
+		cfg.Application = v
+	}
+	if v := getenv("PUSHWOOSH_AUTH_TOKEN"); len(v) > 0 {
+		cfg.AuthToken = v
+	}
+	if v := getenv("PUSHWOOSH_BASE_URL"); len(v) > 0 {
+		cfg.BaseURL = v
+	}
+	return cfg, nil
+}
+
+func newClient(cfg config) (*pushwoosh.Client, error) {
+	client := pushwoosh.NewClient(nil)
+	client.Application = cfg.Application
+	client.AuthToken = cfg.AuthToken
+	client.UserAgent += " (cli)"
+	if len(cfg.BaseURL) > 0 {
+		u, err := url.Parse(cfg.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		client.CacheAddrInfo = false
+		client.SetBaseURL(u)
+	}
+	return client, nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+	"github.com/stelapps/go-pushwoosh/pushwoosh/pushwooshtest"
+)
+
+func TestRun_device(t *testing.T) {
+	server := pushwooshtest.NewServer()
+	defer server.Close()
+	env := testEnv(server)
+
+	runTest(t, env, "device", "register", "-hwid", "testHardwareId", "-token", "testPushToken", "-type", "ios", "-language", "es")
+	runTest(t, env, "device", "tags", "set", "-hwid", "testHardwareId", "Country=es", "cart_value=50", "vip=true")
+	runTest(t, env, "device", "badge", "set", "-hwid", "testHardwareId", "3")
+
+	device, ok := server.Device(pushwooshtest.Application, "testHardwareId")
+	if !ok {
+		t.Fatalf("Expected the device to be registered")
+	}
+	want := map[string]interface{}{"Country": "es", "cart_value": 50.0, "vip": true}
+	if device.Type != pushwoosh.IOS || device.Language != "es" || device.Badge != 3 || !reflect.DeepEqual(device.Tags, want) {
+		t.Errorf("Device = %+v", device)
+	}
+
+	runTest(t, env, "device", "unregister", "-hwid", "testHardwareId")
+	if len(server.Devices()) != 0 {
+		t.Errorf("Expected the device to be unregistered")
+	}
+}
+
+func TestRun_deviceImport(t *testing.T) {
+	server := pushwooshtest.NewServer()
+	defer server.Close()
+	env := testEnv(server)
+
+	dir, err := ioutil.TempDir("", "pushwoosh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mapping := filepath.Join(dir, "mapping.json")
+	ioutil.WriteFile(mapping, []byte(`{"hwid": "id", "push_token": "token", "device_type": "platform", "tags": {"country": "Country"}}`), 0600)
+	devices := filepath.Join(dir, "devices.csv")
+	ioutil.WriteFile(devices, []byte("id,token,platform,country\na,tokenA,iOS,es\nb,tokenB,Android,fr\n"), 0600)
+
+	out := runTest(t, env, "device", "import", "-mapping", mapping, "-state", filepath.Join(dir, "state.json"), devices)
+	var result pushwoosh.ImportResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil || result.Imported != 2 {
+		t.Errorf("Output = %s, want 2 imported", out)
+	}
+	if d, ok := server.Device(pushwooshtest.Application, "b"); !ok || d.Tags["Country"] != "fr" {
+		t.Errorf("Device = %+v, want the imported device", d)
+	}
+}
+
+func TestRun_message(t *testing.T) {
+	server := pushwooshtest.NewServer()
+	defer server.Close()
+	env := testEnv(server)
+
+	out := runTest(t, env, "message", "send", "-content", "Hello", "-devices", "a,b", "-platforms", "iOS,Android")
+	var resp pushwoosh.MessagesResponse
+	if err := json.Unmarshal([]byte(out), &resp); err != nil || len(resp.Info.Messages) != 1 {
+		t.Fatalf("Output = %s, want the message code", out)
+	}
+	code := resp.Info.Messages[0]
+
+	message := server.SentMessages()[0]
+	if !reflect.DeepEqual(message.Notification.Devices, []string{"a", "b"}) ||
+		!reflect.DeepEqual(message.Notification.Platforms, []pushwoosh.DeviceType{pushwoosh.IOS, pushwoosh.Android}) {
+		t.Errorf("Notification = %+v", message.Notification)
+	}
+
+	out = runTest(t, env, "-output", "table", "message", "stats", code)
+	if !strings.HasPrefix(out, "FIELD") || !strings.Contains(out, "status_code") {
+		t.Errorf("Output = %s, want a table", out)
+	}
+	runTest(t, env, "message", "delete", code)
+	if !server.SentMessages()[0].Deleted {
+		t.Errorf("Expected the message to be deleted")
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	server := pushwooshtest.NewServer()
+	defer server.Close()
+	env := testEnv(server)
+
+	tests := [][]string{
+		{},
+		{"device"},
+		{"device", "badge", "set", "-hwid", "testHardwareId"},
+		{"device", "tags", "set", "-hwid", "testHardwareId", "novalue"},
+		{"-output", "xml", "message", "stats", "code"},
+	}
+	for _, args := range tests {
+		if err := run(args, env, ioutil.Discard); err != errUsage {
+			t.Errorf("run(%q) = %v, want %v", args, err, errUsage)
+		}
+	}
+
+	err := run([]string{"device", "badge", "set", "-hwid", "missing", "1"}, env, ioutil.Discard)
+	if e, ok := err.(pushwoosh.ErrorResponse); !ok || e.Status != 210 {
+		t.Errorf("Error = %v, want an API error", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pushwoosh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".pushwoosh.json")
+	ioutil.WriteFile(path, []byte(`{"application": "fileApp", "auth_token": "fileToken"}`), 0600)
+
+	env := map[string]string{"HOME": dir, "PUSHWOOSH_AUTH_TOKEN": "envToken"}
+	cfg, err := loadConfig("", func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if want := (config{Application: "fileApp", AuthToken: "envToken"}); cfg != want {
+		t.Errorf("Config = %+v, want %+v", cfg, want)
+	}
+
+	if _, err := loadConfig(filepath.Join(dir, "missing.json"), func(string) string { return "" }); err == nil {
+		t.Errorf("Expected an error for a missing explicit configuration file")
+	}
+	if _, err := loadConfig("", func(k string) string { return map[string]string{"HOME": "/nonexistent"}[k] }); err != nil {
+		t.Errorf("Expected no error for a missing default configuration file, found %s", err.Error())
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var resp pushwoosh.MessageStatsResponse
+	resp.Status = 200
+	resp.Message = "OK"
+	resp.Info.Rows = []pushwoosh.CampaignStat{{Date: "2013-11-14 00:00:00", Action: "open", Count: 1000000}}
+
+	out := new(bytes.Buffer)
+	if err := writeTable(out, resp); err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	want := strings.Join([]string{
+		"FIELD                     VALUE",
+		"response.rows.0.action    open",
+		"response.rows.0.count     1000000",
+		"response.rows.0.datetime  2013-11-14 00:00:00",
+		"status_code               200",
+		"status_message            OK",
+		"",
+	}, "\n")
+	if out.String() != want {
+		t.Errorf("Table =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func testEnv(server *pushwooshtest.Server) func(string) string {
+	env := map[string]string{
+		"PUSHWOOSH_APPLICATION": pushwooshtest.Application,
+		"PUSHWOOSH_AUTH_TOKEN":  pushwooshtest.AuthToken,
+		"PUSHWOOSH_BASE_URL":    server.Client().BaseURL().String(),
+	}
+	return func(k string) string { return env[k] }
+}
+
+func runTest(t *testing.T, env func(string) string, args ...string) string {
+	out := new(bytes.Buffer)
+	if err := run(args, env, out); err != nil {
+		t.Fatalf("run(%q) = %v", args, err)
+	}
+	return out.String()
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+func writeJSON(w io.Writer, resp interface{}) error {
+	b, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// writeTable writes the response as FIELD VALUE rows, nested fields being
+// named by their path, e.g. "response.rows.0.count".
+func writeTable(w io.Writer, resp interface{}) error {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVALUE")
+	writeRows(tw, "", v)
+	return tw.Flush()
+}
+
+func writeRows(w io.Writer, prefix string, v interface{}) {
+	join := func(key string) string {
+		if len(prefix) <= 0 {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeRows(w, join(k), value[k])
+		}
+	case []interface{}:
+		for i, item := range value {
+			writeRows(w, join(strconv.Itoa(i)), item)
+		}
+	case nil:
+	case float64:
+		fmt.Fprintf(w, "%s\t%s\n", prefix, strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		fmt.Fprintf(w, "%s\t%v\n", prefix, value)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+// ApplicationInfo describes a Pushwoosh application. Unlike the rest of
+// services, ApplicationsService works on any application of the account,
+// not only on Client.Application.
+type ApplicationInfo struct {
+	Code      string       `json:"code,omitempty"`
+	Title     string       `json:"title,omitempty"`
+	Icon      string       `json:"icon,omitempty"`
+	Platforms []DeviceType `json:"platforms,omitempty"`
+}
+
+// APNsCredentials configures iOS pushes through an APNs auth key.
+type APNsCredentials struct {
+	KeyId      string `json:"key_id"`
+	TeamId     string `json:"team_id"`
+	BundleId   string `json:"bundle_id"`
+	Key        string `json:"key"`
+	Production bool   `json:"production"`
+}
+
+// FCMCredentials configures Android pushes through a Firebase service
+// account.
+type FCMCredentials struct {
+	ProjectId      string `json:"project_id"`
+	ServiceAccount string `json:"service_account"`
+}
+
+// PlatformCredentials holds the credentials of the platforms to configure.
+// Platforms left nil are not changed.
+type PlatformCredentials struct {
+	IOS     *APNsCredentials `json:"ios,omitempty"`
+	Android *FCMCredentials  `json:"android,omitempty"`
+}
+
+type ApplicationResponse struct {
+	Response
+	Info struct {
+		Application ApplicationInfo `json:"application,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type ApplicationsResponse struct {
+	Response
+	Info struct {
+		Applications []ApplicationInfo `json:"applications,omitempty"`
+		Total        int               `json:"total,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type ApplicationsService struct {
+	client *Client
+}
+
+// Create adds a new application to the account. Its code is returned in
+// the response.
+func (s ApplicationsService) Create(app ApplicationInfo) (*ApplicationResponse, error) {
+	if len(app.Title) <= 0 {
+		return nil, errors.New("Application title is required")
+	}
+	app.Code = ""
+	body := struct {
+		ApplicationInfo
+	}{app}
+	return s.do("/createApplication", body)
+}
+
+// Update changes the fields set in app, leaving the rest as they are.
+func (s ApplicationsService) Update(app ApplicationInfo) (*Response, error) {
+	if len(app.Code) <= 0 {
+		return nil, errors.New("Application code is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+		ApplicationInfo
+	}{app.Code, app}
+	body.Code = ""
+	req, err := s.client.NewAuthRequest("POST", "/updateApplication", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s ApplicationsService) Get(code string) (*ApplicationResponse, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Application code is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+	}{code}
+	return s.do("/getApplication", body)
+}
+
+// List returns a page of the applications of the account. Pages start at 1.
+func (s ApplicationsService) List(page int) (*ApplicationsResponse, error) {
+	if page <= 0 {
+		page = 1
+	}
+	body := struct {
+		Page int `json:"page"`
+	}{page}
+	req, err := s.client.NewAuthRequest("POST", "/getApplications", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(ApplicationsResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s ApplicationsService) Delete(code string) (*Response, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Application code is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+	}{code}
+	req, err := s.client.NewAuthRequest("POST", "/deleteApplication", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// Configure sets the push credentials of the application platforms.
+func (s ApplicationsService) Configure(code string, credentials PlatformCredentials) (*Response, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Application code is required")
+	}
+	if err := checkPlatformCredentials(credentials); err != nil {
+		return nil, err
+	}
+	body := struct {
+		Application string `json:"application"`
+		PlatformCredentials
+	}{code, credentials}
+	req, err := s.client.NewAuthRequest("POST", "/configureApplication", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s ApplicationsService) do(urlStr string, body interface{}) (*ApplicationResponse, error) {
+	req, err := s.client.NewAuthRequest("POST", urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(ApplicationResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func checkPlatformCredentials(credentials PlatformCredentials) error {
+	if credentials.IOS == nil && credentials.Android == nil {
+		return errors.New("Platform credentials are required")
+	}
+	if c := credentials.IOS; c != nil {
+		if len(c.KeyId) <= 0 || len(c.TeamId) <= 0 || len(c.BundleId) <= 0 || len(c.Key) <= 0 {
+			return errors.New("APNs key ID, team ID, bundle ID and key are required")
+		}
+	}
+	if c := credentials.Android; c != nil {
+		if len(c.ProjectId) <= 0 || len(c.ServiceAccount) <= 0 {
+			return errors.New("FCM project ID and service account are required")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestApplicationsService_Create(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createApplication", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"auth":      "testAuthToken",
+			"title":     "Tenant",
+			"platforms": []interface{}{1.0, 3.0},
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+
+		var res ApplicationResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Application.Code = "XXXXX-XXXXX"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Applications.Create(ApplicationInfo{
+		Code:      "ignored",
+		Title:     "Tenant",
+		Platforms: []DeviceType{IOS, Android},
+	})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.Application.Code != "XXXXX-XXXXX" {
+		t.Errorf("Code = %s, want %s", resp.Info.Application.Code, "XXXXX-XXXXX")
+	}
+}
+
+func TestApplicationsService_Create_invalidTitle(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Applications.Create(ApplicationInfo{})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestApplicationsService_Update(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/updateApplication", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		want := map[string]interface{}{
+			"auth":        "testAuthToken",
+			"application": "XXXXX-XXXXX",
+			"title":       "Renamed",
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.AuthToken = "testAuthToken"
+	_, err := client.Applications.Update(ApplicationInfo{Code: "XXXXX-XXXXX", Title: "Renamed"})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestApplicationsService_Update_keepsTitle(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/updateApplication", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if _, ok := body.Request["title"]; ok {
+			t.Errorf("Request = %v, want no title", body.Request)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.AuthToken = "testAuthToken"
+	_, err := client.Applications.Update(ApplicationInfo{Code: "XXXXX-XXXXX", Icon: "https://example.com/icon.png"})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestApplicationsService_Get(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/getApplication", func(w http.ResponseWriter, r *http.Request) {
+		var res ApplicationResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Application = ApplicationInfo{Code: "XXXXX-XXXXX", Title: "Tenant"}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Applications.Get("XXXXX-XXXXX")
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := ApplicationInfo{Code: "XXXXX-XXXXX", Title: "Tenant"}
+	if !reflect.DeepEqual(resp.Info.Application, want) {
+		t.Errorf("Application = %v, want %v", resp.Info.Application, want)
+	}
+}
+
+func TestApplicationsService_List(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/getApplications", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Page int `json:"page"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Request.Page != 1 {
+			t.Errorf("Page = %d, want %d", body.Request.Page, 1)
+		}
+
+		var res ApplicationsResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Applications = []ApplicationInfo{{Code: "XXXXX-XXXXX", Title: "Tenant"}}
+		res.Info.Total = 1
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Applications.List(0)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.Total != 1 || len(resp.Info.Applications) != 1 {
+		t.Errorf("Applications = %v, want 1 application", resp.Info.Applications)
+	}
+}
+
+func TestApplicationsService_Delete_invalidCode(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Applications.Delete("")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestApplicationsService_Configure(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/configureApplication", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Auth        string           `json:"auth"`
+				Application string           `json:"application"`
+				IOS         *APNsCredentials `json:"ios"`
+				Android     *FCMCredentials  `json:"android"`
+			} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		if body.Request.Application != "XXXXX-XXXXX" {
+			t.Errorf("Application = %s, want %s", body.Request.Application, "XXXXX-XXXXX")
+		}
+
+		if body.Request.IOS != nil {
+			t.Errorf("IOS = %v, want none", body.Request.IOS)
+		}
+
+		want := &FCMCredentials{"project", "{}"}
+		if !reflect.DeepEqual(body.Request.Android, want) {
+			t.Errorf("Android = %v, want %v", body.Request.Android, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.AuthToken = "testAuthToken"
+	_, err := client.Applications.Configure("XXXXX-XXXXX", PlatformCredentials{
+		Android: &FCMCredentials{"project", "{}"},
+	})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestApplicationsService_Configure_invalidCredentials(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	tests := []PlatformCredentials{
+		{},
+		{IOS: &APNsCredentials{KeyId: "key"}},
+		{Android: &FCMCredentials{ProjectId: "project"}},
+	}
+	for _, credentials := range tests {
+		_, err := client.Applications.Configure("XXXXX-XXXXX", credentials)
+		if err == nil {
+			t.Errorf("Expected an error for %v", credentials)
+		}
+	}
+}
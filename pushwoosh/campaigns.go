@@ -0,0 +1,131 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+// Campaign groups messages for reporting. Messages join a campaign through
+// Notification.Campaign.
+type Campaign struct {
+	Code        string `json:"code,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// CampaignStat counts the subscribers taking an action ("send", "delivery",
+// "open", ...) on a campaign message, or on a single message, within a
+// period.
+type CampaignStat struct {
+	Date     string     `json:"datetime"`
+	Platform DeviceType `json:"platform,omitempty"`
+	Action   string     `json:"action"`
+	Count    int        `json:"count"`
+}
+
+type CampaignResponse struct {
+	Response
+	Info struct {
+		Campaign string `json:"campaign,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type CampaignsResponse struct {
+	Response
+	Info struct {
+		Campaigns []Campaign `json:"campaigns,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type CampaignStatsResponse struct {
+	Response
+	Info struct {
+		Rows []CampaignStat `json:"rows,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type CampaignsService struct {
+	client *Client
+}
+
+// Create adds a campaign to the application. The code of the new campaign
+// is returned in the response.
+func (s CampaignsService) Create(campaign Campaign) (*CampaignResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(campaign.Name) <= 0 {
+		return nil, errors.New("Campaign name is required")
+	}
+	campaign.Code = ""
+	body := struct {
+		Application string `json:"application"`
+		Campaign
+	}{s.client.Application, campaign}
+	req, err := s.client.NewAuthRequest("POST", "/createCampaign", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(CampaignResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s CampaignsService) List() (*CampaignsResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+	}{s.client.Application}
+	req, err := s.client.NewAuthRequest("POST", "/getCampaigns", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(CampaignsResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s CampaignsService) Delete(code string) (*Response, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Campaign code is required")
+	}
+	body := struct {
+		Campaign string `json:"campaign"`
+	}{code}
+	req, err := s.client.NewAuthRequest("POST", "/deleteCampaign", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// Stats returns the campaign statistics between two dates, given in the
+// "2006-01-02 15:04:05" layout.
+func (s CampaignsService) Stats(code, from, to string) (*CampaignStatsResponse, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Campaign code is required")
+	}
+	if len(from) <= 0 || len(to) <= 0 {
+		return nil, errors.New("Statistics period is required")
+	}
+	body := struct {
+		Campaign string `json:"campaign"`
+		From     string `json:"datetime_from"`
+		To       string `json:"datetime_to"`
+	}{code, from, to}
+	req, err := s.client.NewAuthRequest("POST", "/getCampaignStats", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(CampaignStatsResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
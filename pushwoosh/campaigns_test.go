@@ -0,0 +1,148 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCampaignsService_Create(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createCampaign", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"auth":        "testAuthToken",
+			"application": "testAppToken",
+			"name":        "transactional",
+			"description": "Order updates",
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+
+		var res CampaignResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Campaign = "testCampaign"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Campaigns.Create(Campaign{Name: "transactional", Description: "Order updates"})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.Campaign != "testCampaign" {
+		t.Errorf("Campaign = %s, want %s", resp.Info.Campaign, "testCampaign")
+	}
+}
+
+func TestCampaignsService_Create_invalidName(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Campaigns.Create(Campaign{})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestCampaignsService_List(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	campaigns := []Campaign{{Code: "testCampaign", Name: "transactional"}}
+	mux.HandleFunc("/getCampaigns", func(w http.ResponseWriter, r *http.Request) {
+		var res CampaignsResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Campaigns = campaigns
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Campaigns.List()
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !reflect.DeepEqual(resp.Info.Campaigns, campaigns) {
+		t.Errorf("Campaigns = %v, want %v", resp.Info.Campaigns, campaigns)
+	}
+}
+
+func TestCampaignsService_Delete_invalidCode(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Campaigns.Delete("")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestCampaignsService_Stats(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	rows := []CampaignStat{
+		{Date: "2013-11-14 00:00:00", Platform: IOS, Action: "send", Count: 10},
+		{Date: "2013-11-14 00:00:00", Platform: IOS, Action: "open", Count: 3},
+	}
+	mux.HandleFunc("/getCampaignStats", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		want := map[string]interface{}{
+			"auth":          "testAuthToken",
+			"campaign":      "testCampaign",
+			"datetime_from": "2013-11-14 00:00:00",
+			"datetime_to":   "2013-11-15 00:00:00",
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+
+		var res CampaignStatsResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Rows = rows
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Campaigns.Stats("testCampaign", "2013-11-14 00:00:00", "2013-11-15 00:00:00")
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !reflect.DeepEqual(resp.Info.Rows, rows) {
+		t.Errorf("Rows = %v, want %v", resp.Info.Rows, rows)
+	}
+}
+
+func TestCampaignsService_Stats_invalidPeriod(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Campaigns.Stats("testCampaign", "", "")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
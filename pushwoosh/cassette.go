@@ -0,0 +1,204 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Interaction is a request and its response, as stored in a cassette.
+// Request and JSON responses are stored with redactedFields redacted.
+type Interaction struct {
+	Method string `json:"method"`
+	// Path is the endpoint, relative to the client base URL, e.g.
+	// "/setTags".
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Status int             `json:"status"`
+	// Response is the response body when it is JSON, ResponseText otherwise.
+	Response     json.RawMessage `json:"response,omitempty"`
+	ResponseText string          `json:"response_text,omitempty"`
+}
+
+// Cassette records the requests made by a client to a golden file, or
+// replays them from it, so tests capture real API responses once and run
+// offline afterwards:
+//
+//	cassette, err := LoadCassette("testdata/set_tags.json")
+//	client.Use(cassette.Middleware(client))
+//
+// Replayed requests are matched on method, endpoint and request body, with
+// redactedFields redacted, whatever the base URL of the client, so tokens,
+// hardware IDs and device lists never reach the file. Identical requests get
+// the recorded responses in order.
+type Cassette struct {
+	path      string
+	recording bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewCassette returns an empty cassette recording real requests, written to
+// path by Save.
+func NewCassette(path string) *Cassette {
+	return &Cassette{path: path, recording: true}
+}
+
+// LoadCassette reads the cassette at path for replay.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("Invalid cassette %s: %s", path, err)
+	}
+	// Bodies are indented in the file, but matched compacted.
+	for i, in := range file.Interactions {
+		if len(in.Body) > 0 {
+			body := new(bytes.Buffer)
+			if err := json.Compact(body, in.Body); err != nil {
+				return nil, err
+			}
+			file.Interactions[i].Body = body.Bytes()
+		}
+	}
+	return &Cassette{
+		path:         path,
+		interactions: file.Interactions,
+		used:         make([]bool, len(file.Interactions)),
+	}, nil
+}
+
+// Interactions returns the interactions of the cassette.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette path.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	file := struct {
+		Interactions []Interaction `json:"interactions"`
+	}{c.interactions}
+	b, err := json.MarshalIndent(file, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, append(b, '\n'), 0644)
+}
+
+// Middleware returns the middleware recording or replaying the requests of
+// client. It should be the innermost one.
+func (c *Cassette) Middleware(client *Client) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := scrubbedRequestBody(req)
+			if err != nil {
+				return nil, err
+			}
+			endpoint := endpointName(client, req)
+			if c.recording {
+				return c.record(next, req, endpoint, body)
+			}
+			return c.replay(req, endpoint, body)
+		})
+	}
+}
+
+func (c *Cassette) record(next http.RoundTripper, req *http.Request, endpoint string, body json.RawMessage) (*http.Response, error) {
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	in := Interaction{
+		Method: req.Method,
+		Path:   endpoint,
+		Body:   body,
+		Status: resp.StatusCode,
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err == nil {
+		in.Response, _ = json.Marshal(redactValue(v))
+	} else {
+		in.ResponseText = string(b)
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, in)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, endpoint string, body json.RawMessage) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, in := range c.interactions {
+		if c.used[i] || in.Method != req.Method || in.Path != endpoint || !bytes.Equal(in.Body, body) {
+			continue
+		}
+		c.used[i] = true
+
+		b := []byte(in.ResponseText)
+		if len(in.Response) > 0 {
+			b = in.Response
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode:    in.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{},
+			Body:          ioutil.NopCloser(bytes.NewReader(b)),
+			ContentLength: int64(len(b)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("No recorded interaction for %s %s %s in cassette %s", req.Method, endpoint, body, c.path)
+}
+
+// scrubbedRequestBody returns the request body normalized, with its keys
+// sorted and redactedFields redacted.
+func scrubbedRequestBody(req *http.Request) (json.RawMessage, error) {
+	if req.GetBody == nil {
+		return nil, nil
+	}
+	r, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil || len(b) <= 0 {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(v))
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	mux, server, client := sandbox()
+
+	calls := 0
+	mux.HandleFunc("/setTags", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		resp := TagsResponse{Response: Response{Status: 200, Message: "OK"}}
+		if calls > 1 {
+			resp.Info.Skipped = []SkippedTag{{Tag: "foo", Reason: "bar"}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	dir, err := ioutil.TempDir("", "pushwoosh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	recorder := NewCassette(path)
+	client.Use(recorder.Middleware(client))
+	client.Application = "testAppToken"
+	device := Device{HardwareId: "testHardwareId"}
+	client.Devices.SetTags(device)
+	client.Devices.SetTags(device)
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	server.Close()
+
+	b, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(b), "testAppToken") {
+		t.Errorf("Cassette = %s, want the application scrubbed", b)
+	}
+
+	if in := recorder.Interactions(); len(in) != 2 || in[0].Path != "/setTags" {
+		t.Errorf("Interactions = %+v, want 2 on /setTags", in)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	replay := NewClient(nil)
+	otherURL, _ := url.Parse("https://example.com/api/")
+	replay.SetBaseURL(otherURL)
+	replay.Use(cassette.Middleware(replay))
+	replay.Application = "otherAppToken"
+
+	for _, want := range []int{0, 1} {
+		resp, err := replay.Devices.SetTags(device)
+		if err != nil {
+			t.Fatalf("Expected no error, found %s", err.Error())
+		}
+		if len(resp.Info.Skipped) != want {
+			t.Errorf("Skipped = %v, want %d tags", resp.Info.Skipped, want)
+		}
+	}
+	if _, err := replay.Devices.SetTags(device); err == nil {
+		t.Errorf("Expected an error once the interactions are used")
+	}
+	if _, err := replay.Devices.SetBadge(device, 3); err == nil {
+		t.Errorf("Expected an error for an unrecorded request")
+	}
+}
+
+func TestCassette_redactsDevices(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	dir, err := ioutil.TempDir("", "pushwoosh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	recorder := NewCassette(path)
+	client.Use(recorder.Middleware(client))
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	if _, err := client.Messages.Create(Notification{Content: Text("Hello"), Devices: []string{"PUSHTOKEN-SECRET"}}); err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if _, err := client.Devices.Unregister("HWID-SECRET"); err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+
+	b, _ := ioutil.ReadFile(path)
+	for _, secret := range []string{"PUSHTOKEN-SECRET", "HWID-SECRET", "testAuthToken"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("Cassette = %s, want %s scrubbed", b, secret)
+		}
+	}
+}
+
+func TestLoadCassette_invalid(t *testing.T) {
+	if _, err := LoadCassette("missing.json"); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
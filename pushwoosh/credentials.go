@@ -0,0 +1,121 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies the API access token. It is asked once per
+// request, so implementations are free to change the token at any time
+// without restarting the client.
+type CredentialsProvider interface {
+	AuthToken() (string, error)
+}
+
+// StaticCredentials is a fixed API access token.
+type StaticCredentials string
+
+func (c StaticCredentials) AuthToken() (string, error) {
+	return string(c), nil
+}
+
+// EnvCredentials reads the API access token from the named environment
+// variable on every request.
+type EnvCredentials string
+
+func (c EnvCredentials) AuthToken() (string, error) {
+	token := os.Getenv(string(c))
+	if len(token) <= 0 {
+		return "", fmt.Errorf("Environment variable %s is empty", string(c))
+	}
+	return token, nil
+}
+
+// FileCredentials reads the API access token from a file, reloading it
+// whenever the file changes. Surrounding whitespace is ignored.
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+func (c *FileCredentials) AuthToken() (string, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.token) > 0 && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.token, nil
+	}
+	b, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(b))
+	if len(token) <= 0 {
+		return "", fmt.Errorf("Credentials file %s is empty", c.path)
+	}
+	c.token, c.modTime, c.size = token, info.ModTime(), info.Size()
+	return c.token, nil
+}
+
+// RotatingCredentials holds an API access token that expires. Once expired,
+// the refresh function is asked for a new token and its expiration time.
+// The token can also be replaced at any time with Rotate.
+type RotatingCredentials struct {
+	refresh func() (string, time.Time, error)
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewRotatingCredentials returns credentials fetching their token from
+// refresh. A nil refresh means the token only changes through Rotate.
+func NewRotatingCredentials(refresh func() (string, time.Time, error)) *RotatingCredentials {
+	return &RotatingCredentials{refresh: refresh}
+}
+
+// Rotate replaces the token. A zero expires means it never expires.
+func (c *RotatingCredentials) Rotate(token string, expires time.Time) {
+	c.mu.Lock()
+	c.token, c.expires = token, expires
+	c.mu.Unlock()
+}
+
+func (c *RotatingCredentials) AuthToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expired := !c.expires.IsZero() && !time.Now().Before(c.expires)
+	if len(c.token) > 0 && !expired {
+		return c.token, nil
+	}
+	if c.refresh == nil {
+		return "", errors.New("Auth token expired")
+	}
+	token, expires, err := c.refresh()
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expires = token, expires
+	return c.token, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStaticCredentials(t *testing.T) {
+	token, err := StaticCredentials("foo").AuthToken()
+	if err != nil || token != "foo" {
+		t.Errorf("AuthToken() = %s, %v, want %s", token, err, "foo")
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	os.Setenv("PUSHWOOSH_TEST_AUTH", "foo")
+	defer os.Unsetenv("PUSHWOOSH_TEST_AUTH")
+
+	token, err := EnvCredentials("PUSHWOOSH_TEST_AUTH").AuthToken()
+	if err != nil || token != "foo" {
+		t.Errorf("AuthToken() = %s, %v, want %s", token, err, "foo")
+	}
+
+	os.Setenv("PUSHWOOSH_TEST_AUTH", "")
+	if _, err := EnvCredentials("PUSHWOOSH_TEST_AUTH").AuthToken(); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pushwoosh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "auth")
+
+	credentials := NewFileCredentials(path)
+	if _, err := credentials.AuthToken(); err == nil {
+		t.Errorf("Expected an error")
+	}
+
+	ioutil.WriteFile(path, []byte("foo\n"), 0600)
+	token, err := credentials.AuthToken()
+	if err != nil || token != "foo" {
+		t.Errorf("AuthToken() = %s, %v, want %s", token, err, "foo")
+	}
+
+	ioutil.WriteFile(path, []byte("foobar\n"), 0600)
+	later := time.Now().Add(time.Second)
+	os.Chtimes(path, later, later)
+	token, err = credentials.AuthToken()
+	if err != nil || token != "foobar" {
+		t.Errorf("AuthToken() = %s, %v, want %s", token, err, "foobar")
+	}
+}
+
+func TestRotatingCredentials(t *testing.T) {
+	refreshes := 0
+	credentials := NewRotatingCredentials(func() (string, time.Time, error) {
+		refreshes++
+		if refreshes > 2 {
+			return "", time.Time{}, errors.New("foo")
+		}
+		return "token", time.Now().Add(-time.Second), nil
+	})
+
+	for i := 0; i < 2; i++ {
+		token, err := credentials.AuthToken()
+		if err != nil || token != "token" {
+			t.Errorf("AuthToken() = %s, %v, want %s", token, err, "token")
+		}
+	}
+	if refreshes != 2 {
+		t.Errorf("Refreshes = %d, want %d", refreshes, 2)
+	}
+	if _, err := credentials.AuthToken(); err == nil {
+		t.Errorf("Expected an error")
+	}
+
+	credentials.Rotate("rotated", time.Time{})
+	token, err := credentials.AuthToken()
+	if err != nil || token != "rotated" {
+		t.Errorf("AuthToken() = %s, %v, want %s", token, err, "rotated")
+	}
+	if refreshes != 3 {
+		t.Errorf("Refreshes = %d, want %d", refreshes, 3)
+	}
+}
+
+func TestRotatingCredentials_noRefresh(t *testing.T) {
+	credentials := NewRotatingCredentials(nil)
+	if _, err := credentials.AuthToken(); err == nil {
+		t.Errorf("Expected an error")
+	}
+	credentials.Rotate("token", time.Now().Add(time.Hour))
+	token, err := credentials.AuthToken()
+	if err != nil || token != "token" {
+		t.Errorf("AuthToken() = %s, %v, want %s", token, err, "token")
+	}
+}
@@ -7,6 +7,12 @@ package pushwoosh
 
 import (
 	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 type Device struct {
@@ -26,8 +32,60 @@ const (
 	Nokia        DeviceType = 4
 	WindowsPhone DeviceType = 5
 	OSX          DeviceType = 7
+	Windows      DeviceType = 8
+	Amazon       DeviceType = 9
+	Safari       DeviceType = 10
+	Chrome       DeviceType = 11
+	Firefox      DeviceType = 12
+	EmailDevice  DeviceType = 14
+	Huawei       DeviceType = 17
+	SMS          DeviceType = 18
 )
 
+var deviceTypeNames = map[DeviceType]string{
+	IOS:          "iOS",
+	BlackBerry:   "BlackBerry",
+	Android:      "Android",
+	Nokia:        "Nokia",
+	WindowsPhone: "WindowsPhone",
+	OSX:          "OSX",
+	Windows:      "Windows",
+	Amazon:       "Amazon",
+	Safari:       "Safari",
+	Chrome:       "Chrome",
+	Firefox:      "Firefox",
+	EmailDevice:  "Email",
+	Huawei:       "Huawei",
+	SMS:          "SMS",
+}
+
+func (t DeviceType) String() string {
+	if name, ok := deviceTypeNames[t]; ok {
+		return name
+	}
+	return strconv.Itoa(int(t))
+}
+
+// ParseDeviceType parses either the name of a device type, as returned by
+// DeviceType.String, or its numeric value. Names are case insensitive.
+func ParseDeviceType(s string) (DeviceType, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		return DeviceType(n), nil
+	}
+	name := strings.ToLower(strings.Replace(s, "_", "", -1))
+	switch name {
+	case "mac", "macos":
+		return OSX, nil
+	}
+	for t, n := range deviceTypeNames {
+		if strings.ToLower(n) == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown device type %q", s)
+}
+
 type Identifiable interface {
 	DeviceId() string
 }
@@ -122,6 +180,34 @@ func checkDevice(device Registrable) error {
 	if device.DeviceType() == 0 {
 		return errors.New("Device Type is required")
 	}
+	if webPushAspect, ok := device.(WebPushRegistrable); ok {
+		if err := checkWebPushKeys(device.DeviceType(), webPushAspect); err != nil {
+			return err
+		}
+	}
+	return checkPushToken(device.DeviceType(), device.DevicePushToken())
+}
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// checkPushToken validates the push token formats of the channels not
+// backed by an opaque platform token.
+func checkPushToken(t DeviceType, token string) error {
+	switch t {
+	case SMS:
+		if !e164.MatchString(token) {
+			return errors.New("SMS Push Token must be an E.164 phone number")
+		}
+	case EmailDevice:
+		if _, err := mail.ParseAddress(token); err != nil {
+			return errors.New("Email Push Token must be an email address")
+		}
+	case Chrome, Firefox:
+		u, err := url.Parse(token)
+		if err != nil || u.Scheme != "https" || len(u.Host) <= 0 {
+			return fmt.Errorf("%s Push Token must be an HTTPS endpoint URL", t)
+		}
+	}
 	return nil
 }
 
@@ -133,6 +219,8 @@ func newRegisterDeviceBody(app string, device Registrable) interface{} {
 		HardwareId  string     `json:"hwid"`
 		TimeZone    int        `json:"timezone,omitempty"`
 		Type        DeviceType `json:"device_type"`
+		PublicKey   string     `json:"public_key,omitempty"`
+		AuthSecret  string     `json:"auth_token,omitempty"`
 	}{}
 	body.Application = app
 	body.HardwareId = device.DeviceId()
@@ -146,5 +234,10 @@ func newRegisterDeviceBody(app string, device Registrable) interface{} {
 	if ok {
 		body.Language = langugageAspect.DeviceLanguage()
 	}
+	webPushAspect, ok := device.(WebPushRegistrable)
+	if ok {
+		body.PublicKey = webPushAspect.WebPushPublicKey()
+		body.AuthSecret = webPushAspect.WebPushAuthSecret()
+	}
 	return body
 }
@@ -0,0 +1,401 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ImportMapping names the columns, or JSON fields, of an import file holding
+// each device field. Tags maps columns to tag names. JSON fields may be
+// nested, e.g. "tags.Country" for files written by ExportsService.
+type ImportMapping struct {
+	HardwareId string            `json:"hwid"`
+	PushToken  string            `json:"push_token"`
+	Type       string            `json:"device_type"`
+	Language   string            `json:"language,omitempty"`
+	TimeZone   string            `json:"timezone,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+// LoadImportMapping reads a JSON mapping file, e.g.
+//
+//	{"hwid": "id", "push_token": "token", "device_type": "platform",
+//	 "tags": {"country": "Country", "cart": "cart_value"}}
+func LoadImportMapping(path string) (ImportMapping, error) {
+	var m ImportMapping
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, fmt.Errorf("Invalid import mapping %s: %s", path, err)
+	}
+	return m, nil
+}
+
+// DefaultImportConcurrency is the number of devices imported at once when
+// ImportOptions.Concurrency is not set.
+const DefaultImportConcurrency = 4
+
+// importCheckpointEvery is the number of records between checkpoints.
+const importCheckpointEvery = 100
+
+// ImportOptions configures DevicesService.Import.
+type ImportOptions struct {
+	// Format is the file format, one of the export formats.
+	Format  ExportFormat
+	Mapping ImportMapping
+	// Concurrency is the number of devices imported at once.
+	Concurrency int
+	// StatePath, when set, is the file where the import progress is saved,
+	// so an interrupted import started again resumes where it stopped.
+	StatePath string
+}
+
+// ImportError is a record that could not be imported. Record counts from 1,
+// not including the CSV header.
+type ImportError struct {
+	Record int    `json:"record"`
+	Error  string `json:"error"`
+}
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	// Resumed is the number of records skipped, imported by a previous run.
+	Resumed  int           `json:"resumed"`
+	Imported int           `json:"imported"`
+	Failed   []ImportError `json:"failed,omitempty"`
+}
+
+// importState is the content of the state file.
+type importState struct {
+	Records int `json:"records"`
+}
+
+// Import registers the devices of a CSV or JSONL file, setting their tags.
+// Records rejected by the API, or invalid, are reported in the result and
+// not retried. Any other error, e.g. a network one, stops the import, which
+// resumes from the first unfinished record when run again with the same
+// StatePath.
+//
+// CSV tag values are taken as JSON when valid, so 3 is a number and true a
+// boolean, and as strings otherwise. Device fields are always strings.
+func (s DevicesService) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if err := checkExportFormat(opts.Format); err != nil {
+		return nil, err
+	}
+	m := opts.Mapping
+	if len(m.HardwareId) <= 0 || len(m.PushToken) <= 0 || len(m.Type) <= 0 {
+		return nil, errors.New("Import mapping of hwid, push_token and device_type is required")
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultImportConcurrency
+	}
+
+	var state importState
+	if len(opts.StatePath) > 0 {
+		b, err := ioutil.ReadFile(opts.StatePath)
+		if err == nil {
+			err = json.Unmarshal(b, &state)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("Invalid import state %s: %s", opts.StatePath, err)
+		}
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	imp := &importer{
+		opts:     opts,
+		result:   &ImportResult{Resumed: state.Records},
+		done:     map[int]bool{},
+		finished: state.Records,
+		saved:    state.Records,
+		cancel:   cancel,
+	}
+
+	type job struct {
+		n   int
+		row map[string]interface{}
+	}
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				imp.finish(j.n, s.importRecord(ctx, opts, j.row))
+			}
+		}()
+	}
+
+	rows := newImportReader(r, opts.Format)
+	var err error
+read:
+	for n := 1; ; n++ {
+		row, rerr := rows.next()
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			err = fmt.Errorf("Record %d: %s", n, rerr)
+			break
+		}
+		if n <= state.Records {
+			continue
+		}
+		select {
+		case jobs <- job{n, row}:
+		case <-ctx.Done():
+			break read
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if imp.err != nil {
+		err = imp.err
+	}
+	if err == nil {
+		err = parent.Err()
+	}
+	if saveErr := imp.save(); err == nil {
+		err = saveErr
+	}
+	return imp.result, err
+}
+
+// importer tracks the progress of an import.
+type importer struct {
+	opts   ImportOptions
+	cancel func()
+
+	mu     sync.Mutex
+	result *ImportResult
+	err    error
+	// done holds the records finished after the first unfinished one.
+	done     map[int]bool
+	finished int
+	saved    int
+}
+
+// finish records the outcome of record n, checkpointing the progress.
+func (imp *importer) finish(n int, err error) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	if err != nil && !isImportRecordError(err) {
+		if imp.err == nil {
+			imp.err = err
+			imp.cancel()
+		}
+		return
+	}
+	if err != nil {
+		imp.result.Failed = append(imp.result.Failed, ImportError{n, err.Error()})
+	} else {
+		imp.result.Imported++
+	}
+	imp.done[n] = true
+	for imp.done[imp.finished+1] {
+		delete(imp.done, imp.finished+1)
+		imp.finished++
+	}
+	if imp.finished-imp.saved >= importCheckpointEvery {
+		if err := imp.saveLocked(); err != nil && imp.err == nil {
+			imp.err = err
+			imp.cancel()
+		}
+	}
+}
+
+func (imp *importer) save() error {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	return imp.saveLocked()
+}
+
+// saveLocked writes the state file, replacing it atomically.
+func (imp *importer) saveLocked() error {
+	if len(imp.opts.StatePath) <= 0 || imp.finished == imp.saved {
+		return nil
+	}
+	b, _ := json.Marshal(importState{imp.finished})
+	tmp, err := ioutil.TempFile(filepath.Dir(imp.opts.StatePath), filepath.Base(imp.opts.StatePath))
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), imp.opts.StatePath)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	imp.saved = imp.finished
+	return nil
+}
+
+// importRecordError is an invalid record.
+type importRecordError struct {
+	error
+}
+
+func isImportRecordError(err error) bool {
+	switch err.(type) {
+	case importRecordError, ErrorResponse:
+		return true
+	}
+	return false
+}
+
+// importedDevice is a device read from an import file.
+type importedDevice struct {
+	Device
+	tags map[string]interface{}
+}
+
+func (d importedDevice) DeviceTags() map[string]interface{} {
+	return d.tags
+}
+
+func (s DevicesService) importRecord(ctx context.Context, opts ImportOptions, row map[string]interface{}) error {
+	device, err := mapImportRecord(opts.Mapping, row, opts.Format == ExportCSV)
+	if err != nil {
+		return importRecordError{err}
+	}
+	if err := checkDevice(device); err != nil {
+		return importRecordError{err}
+	}
+	client := s.client.WithContext(ctx)
+	if _, err := client.Devices.Register(device); err != nil {
+		return err
+	}
+	if len(device.tags) > 0 {
+		if _, err := client.Devices.SetTags(device); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapImportRecord builds the device of a record. Tag values in text records
+// are decoded as JSON when valid.
+func mapImportRecord(m ImportMapping, row map[string]interface{}, text bool) (importedDevice, error) {
+	device := importedDevice{tags: map[string]interface{}{}}
+	str := func(column string) string {
+		if v, ok := importField(row, column); ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	device.HardwareId = str(m.HardwareId)
+	device.PushToken = str(m.PushToken)
+	device.Language = str(m.Language)
+	t, err := ParseDeviceType(str(m.Type))
+	if err != nil {
+		return device, err
+	}
+	device.Type = t
+	if tz := str(m.TimeZone); len(tz) > 0 {
+		if _, err := fmt.Sscan(tz, &device.TimeZone); err != nil {
+			return device, fmt.Errorf("Invalid timezone %q", tz)
+		}
+	}
+	for column, tag := range m.Tags {
+		v, ok := importField(row, column)
+		if !ok {
+			continue
+		}
+		if s, isString := v.(string); text && isString {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(s), &decoded); err == nil {
+				v = decoded
+			}
+		}
+		device.tags[tag] = v
+	}
+	return device, nil
+}
+
+// importField looks a column up, following dots into nested JSON objects.
+func importField(row map[string]interface{}, column string) (interface{}, bool) {
+	if v, ok := row[column]; ok {
+		return v, true
+	}
+	if i := strings.Index(column, "."); i > 0 {
+		if nested, ok := row[column[:i]].(map[string]interface{}); ok {
+			return importField(nested, column[i+1:])
+		}
+	}
+	return nil, false
+}
+
+// importReader reads the records of an import file as maps.
+type importReader struct {
+	csv    *csv.Reader
+	header []string
+	json   *json.Decoder
+}
+
+func newImportReader(r io.Reader, format ExportFormat) *importReader {
+	if format == ExportCSV {
+		rd := csv.NewReader(bufio.NewReader(r))
+		rd.FieldsPerRecord = -1
+		return &importReader{csv: rd}
+	}
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+	return &importReader{json: dec}
+}
+
+func (r *importReader) next() (map[string]interface{}, error) {
+	if r.json != nil {
+		var row map[string]interface{}
+		err := r.json.Decode(&row)
+		return row, err
+	}
+	if r.header == nil {
+		header, err := r.csv.Read()
+		if err != nil {
+			return nil, err
+		}
+		r.header = header
+	}
+	record, err := r.csv.Read()
+	if err != nil {
+		return nil, err
+	}
+	row := map[string]interface{}{}
+	for i, value := range record {
+		if i >= len(r.header) || len(value) <= 0 {
+			continue
+		}
+		row[r.header[i]] = value
+	}
+	return row, nil
+}
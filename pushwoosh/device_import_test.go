@@ -0,0 +1,186 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type importTest struct {
+	mu         sync.Mutex
+	registered []string
+	tags       map[string]map[string]interface{}
+	fail       string
+}
+
+func (it *importTest) handle(mux *http.ServeMux) {
+	it.tags = map[string]map[string]interface{}{}
+	mux.HandleFunc("/registerDevice", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				HardwareId string `json:"hwid"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		it.mu.Lock()
+		defer it.mu.Unlock()
+		switch body.Request.HardwareId {
+		case it.fail:
+			http.Error(w, "Unavailable", http.StatusServiceUnavailable)
+		case "rejected":
+			json.NewEncoder(w).Encode(Response{Status: 210, Message: "Invalid push token"})
+		default:
+			it.registered = append(it.registered, body.Request.HardwareId)
+			json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+		}
+	})
+	mux.HandleFunc("/setTags", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				HardwareId string                 `json:"hwid"`
+				Tags       map[string]interface{} `json:"tags"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		it.mu.Lock()
+		it.tags[body.Request.HardwareId] = body.Request.Tags
+		it.mu.Unlock()
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+}
+
+var importMappingTest = ImportMapping{
+	HardwareId: "id",
+	PushToken:  "token",
+	Type:       "platform",
+	Tags:       map[string]string{"country": "Country", "cart": "cart_value"},
+}
+
+func TestDevicesService_Import(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+	it := new(importTest)
+	it.handle(mux)
+
+	file := strings.Join([]string{
+		"id,token,platform,country,cart",
+		"00123,tokenA,iOS,es,50",
+		"b,tokenB,3,fr,",
+		"c,tokenC,Fridge,de,1",
+		"rejected,tokenD,Android,,",
+		"",
+	}, "\n")
+	client.Application = "testAppToken"
+	result, err := client.Devices.Import(context.Background(), strings.NewReader(file), ImportOptions{
+		Format:      ExportCSV,
+		Mapping:     importMappingTest,
+		Concurrency: 3,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+
+	if result.Imported != 2 || len(result.Failed) != 2 {
+		t.Errorf("Result = %+v, want 2 imported and 2 failed", result)
+	}
+	sort.Slice(result.Failed, func(i, j int) bool { return result.Failed[i].Record < result.Failed[j].Record })
+	if result.Failed[0].Record != 3 || result.Failed[1].Record != 4 {
+		t.Errorf("Failed = %+v, want records 3 and 4", result.Failed)
+	}
+	sort.Strings(it.registered)
+	if want := []string{"00123", "b"}; !reflect.DeepEqual(it.registered, want) {
+		t.Errorf("Registered = %v, want %v", it.registered, want)
+	}
+	want := map[string]map[string]interface{}{
+		"00123": {"Country": "es", "cart_value": 50.0},
+		"b":     {"Country": "fr"},
+	}
+	if !reflect.DeepEqual(it.tags, want) {
+		t.Errorf("Tags = %v, want %v", it.tags, want)
+	}
+}
+
+func TestDevicesService_Import_resume(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+	it := new(importTest)
+	it.handle(mux)
+	it.fail = "c"
+
+	dir, err := ioutil.TempDir("", "pushwoosh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := strings.Join([]string{
+		`{"hwid": "a", "push_token": "tokenA", "type": 1, "tags": {"country": "es"}}`,
+		`{"hwid": "b", "push_token": "tokenB", "type": 1}`,
+		`{"hwid": "c", "push_token": "tokenC", "type": 1}`,
+		`{"hwid": "d", "push_token": "tokenD", "type": 1}`,
+	}, "\n")
+	opts := ImportOptions{
+		Format: ExportJSON,
+		Mapping: ImportMapping{
+			HardwareId: "hwid",
+			PushToken:  "push_token",
+			Type:       "type",
+			Tags:       map[string]string{"tags.country": "Country"},
+		},
+		Concurrency: 1,
+		StatePath:   filepath.Join(dir, "state.json"),
+	}
+	client.Application = "testAppToken"
+
+	result, err := client.Devices.Import(context.Background(), strings.NewReader(file), opts)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if result.Imported != 2 {
+		t.Errorf("Imported = %d, want %d", result.Imported, 2)
+	}
+	if !reflect.DeepEqual(it.tags["a"], map[string]interface{}{"Country": "es"}) {
+		t.Errorf("Tags = %v, want the nested tag", it.tags["a"])
+	}
+
+	it.fail = ""
+	result, err = client.Devices.Import(context.Background(), strings.NewReader(file), opts)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if result.Resumed != 2 || result.Imported != 2 {
+		t.Errorf("Result = %+v, want 2 resumed and 2 imported", result)
+	}
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(it.registered, want) {
+		t.Errorf("Registered = %v, want %v", it.registered, want)
+	}
+	b, _ := ioutil.ReadFile(opts.StatePath)
+	if string(b) != `{"records":4}` {
+		t.Errorf("State = %s, want %s", b, `{"records":4}`)
+	}
+}
+
+func TestDevicesService_Import_invalidMapping(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	_, err := client.Devices.Import(context.Background(), strings.NewReader(""), ImportOptions{
+		Format:  ExportCSV,
+		Mapping: ImportMapping{HardwareId: "id"},
+	})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
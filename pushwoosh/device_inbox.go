@@ -0,0 +1,97 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+type InboxStatus int
+
+const (
+	InboxDelivered InboxStatus = 1
+	InboxRead      InboxStatus = 2
+	InboxOpened    InboxStatus = 3
+	InboxDeleted   InboxStatus = 4
+)
+
+type InboxMessage struct {
+	Id             string      `json:"inbox_id"`
+	Code           string      `json:"code,omitempty"`
+	SendDate       string      `json:"send_date"`
+	ExpirationDate string      `json:"rt,omitempty"`
+	Title          string      `json:"title,omitempty"`
+	Text           string      `json:"text"`
+	Image          string      `json:"image,omitempty"`
+	Status         InboxStatus `json:"status"`
+}
+
+type InboxResponse struct {
+	Response
+	Info struct {
+		Messages []InboxMessage `json:"messages,omitempty"`
+		// Next is the code to be given as lastCode to fetch the next page.
+		Next string `json:"next,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+// InboxMessages returns up to count inbox messages of the device, starting
+// after the message with code lastCode. An empty lastCode starts from the
+// newest message.
+func (s DevicesService) InboxMessages(device Identifiable, lastCode string, count int) (*InboxResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(device.DeviceId()) <= 0 {
+		return nil, errors.New("Device Hardware ID is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+		HardwareId  string `json:"hwid"`
+		LastCode    string `json:"last_code,omitempty"`
+		Count       int    `json:"count,omitempty"`
+	}{s.client.Application, device.DeviceId(), lastCode, count}
+	req, err := s.client.NewRequest("POST", "/getInboxMessages", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(InboxResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s DevicesService) MarkInboxRead(device Identifiable, inboxId string) (*Response, error) {
+	return s.setInboxStatus(device, inboxId, InboxRead)
+}
+
+func (s DevicesService) DeleteInboxMessage(device Identifiable, inboxId string) (*Response, error) {
+	return s.setInboxStatus(device, inboxId, InboxDeleted)
+}
+
+func (s DevicesService) setInboxStatus(device Identifiable, inboxId string, status InboxStatus) (*Response, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(device.DeviceId()) <= 0 {
+		return nil, errors.New("Device Hardware ID is required")
+	}
+	if len(inboxId) <= 0 {
+		return nil, errors.New("Inbox message ID is required")
+	}
+	body := struct {
+		Application string      `json:"application"`
+		HardwareId  string      `json:"hwid"`
+		InboxId     string      `json:"inbox_code"`
+		Status      InboxStatus `json:"status"`
+	}{s.client.Application, device.DeviceId(), inboxId, status}
+	req, err := s.client.NewRequest("POST", "/inboxStatus", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevicesService_InboxMessages(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	messages := []InboxMessage{
+		{Id: "inbox1", SendDate: "2013-11-14 10:00:00", Text: "Hello", Status: InboxDelivered},
+	}
+	mux.HandleFunc("/getInboxMessages", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"application": "testAppToken",
+			"hwid":        "testHardwareId",
+			"last_code":   "inbox0",
+			"count":       10.0,
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+
+		var res InboxResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Messages = messages
+		res.Info.Next = "inbox1"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	resp, err := client.Devices.InboxMessages(Device{HardwareId: "testHardwareId"}, "inbox0", 10)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !reflect.DeepEqual(resp.Info.Messages, messages) {
+		t.Errorf("Messages = %v, want %v", resp.Info.Messages, messages)
+	}
+	if resp.Info.Next != "inbox1" {
+		t.Errorf("Next = %s, want %s", resp.Info.Next, "inbox1")
+	}
+}
+
+func TestDevicesService_InboxMessages_invalidHardwareId(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	_, err := client.Devices.InboxMessages(Device{}, "", 0)
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestDevicesService_InboxStatus(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	var status InboxStatus
+	mux.HandleFunc("/inboxStatus", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				HardwareId string      `json:"hwid"`
+				InboxId    string      `json:"inbox_code"`
+				Status     InboxStatus `json:"status"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if body.Request.HardwareId != "testHardwareId" {
+			t.Errorf("HardwareId = %s, want %s", body.Request.HardwareId, "testHardwareId")
+		}
+		if body.Request.InboxId != "inbox1" {
+			t.Errorf("InboxId = %s, want %s", body.Request.InboxId, "inbox1")
+		}
+		status = body.Request.Status
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	device := Device{HardwareId: "testHardwareId"}
+	if _, err := client.Devices.MarkInboxRead(device, "inbox1"); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if status != InboxRead {
+		t.Errorf("Status = %d, want %d", status, InboxRead)
+	}
+	if _, err := client.Devices.DeleteInboxMessage(device, "inbox1"); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if status != InboxDeleted {
+		t.Errorf("Status = %d, want %d", status, InboxDeleted)
+	}
+}
+
+func TestDevicesService_InboxStatus_invalidId(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	_, err := client.Devices.MarkInboxRead(Device{HardwareId: "testHardwareId"}, "")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
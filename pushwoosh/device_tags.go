@@ -22,6 +22,12 @@ type SkippedTag struct {
 	Reason string `json:"reason"`
 }
 
+// TagsRegistrable is implemented by devices holding their tags in a map
+// rather than in fields with a `tag` struct tag.
+type TagsRegistrable interface {
+	DeviceTags() map[string]interface{}
+}
+
 func (s DevicesService) SetTags(device Identifiable) (*TagsResponse, error) {
 	if len(s.client.Application) <= 0 {
 		return nil, errors.New("Application token is required")
@@ -43,6 +49,9 @@ func (s DevicesService) SetTags(device Identifiable) (*TagsResponse, error) {
 }
 
 func getTags(d interface{}) (map[string]interface{}, error) {
+	if tagsAspect, ok := d.(TagsRegistrable); ok {
+		return tagsAspect.DeviceTags(), nil
+	}
 	v := reflect.ValueOf(d)
 	t := v.Type()
 	tags := map[string]interface{}{}
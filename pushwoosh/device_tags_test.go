@@ -130,6 +130,44 @@ func TestDevicesService_SetTags_skippedTags(t *testing.T) {
 	}
 }
 
+func TestDevicesService_SetTags_map(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/setTags", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Tags map[string]interface{} `json:"tags"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		want := map[string]interface{}{"Country": "es", "cart_value": 50.0}
+		if !reflect.DeepEqual(body.Request.Tags, want) {
+			t.Errorf("Tags = %v, want %v", body.Request.Tags, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	device := deviceTagsMapTest{"testHardwareId", map[string]interface{}{"Country": "es", "cart_value": 50}}
+	if _, err := client.Devices.SetTags(device); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+type deviceTagsMapTest struct {
+	HardwareId string
+	Tags       map[string]interface{}
+}
+
+func (device deviceTagsMapTest) DeviceId() string {
+	return device.HardwareId
+}
+
+func (device deviceTagsMapTest) DeviceTags() map[string]interface{} {
+	return device.Tags
+}
+
 type deviceTagsTest struct {
 	HardwareId string
 	Foo        int    `tag:"foo!"`
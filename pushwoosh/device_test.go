@@ -238,3 +238,69 @@ func TestDevicesService_Unegister_error(t *testing.T) {
 		t.Errorf("Response resp = %v, want %v", resp, want)
 	}
 }
+
+func TestDevicesService_Register_channelPushTokens(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	tests := []struct {
+		device Device
+		valid  bool
+	}{
+		{Device{HardwareId: "hwid", PushToken: "+34600000000", Type: SMS}, true},
+		{Device{HardwareId: "hwid", PushToken: "600000000", Type: SMS}, false},
+		{Device{HardwareId: "hwid", PushToken: "+0600000000", Type: SMS}, false},
+		{Device{HardwareId: "hwid", PushToken: "alice@example.com", Type: EmailDevice}, true},
+		{Device{HardwareId: "hwid", PushToken: "alice", Type: EmailDevice}, false},
+		{Device{HardwareId: "hwid", PushToken: "https://fcm.googleapis.com/fcm/send/abc", Type: Chrome}, true},
+		{Device{HardwareId: "hwid", PushToken: "abc", Type: Chrome}, false},
+		{Device{HardwareId: "hwid", PushToken: "http://updates.push.services.mozilla.com/abc", Type: Firefox}, false},
+	}
+
+	for _, test := range tests {
+		err := checkDevice(test.device)
+		if test.valid && err != nil {
+			t.Errorf("checkDevice(%v): expected no error, found %s", test.device, err.Error())
+		}
+		if !test.valid && err == nil {
+			t.Errorf("checkDevice(%v): expected an error", test.device)
+		}
+	}
+}
+
+func TestParseDeviceType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want DeviceType
+	}{
+		{"1", IOS},
+		{"ios", IOS},
+		{"Windows_Phone", WindowsPhone},
+		{"mac", OSX},
+		{"SMS", SMS},
+		{"email", EmailDevice},
+		{"42", DeviceType(42)},
+	}
+
+	for _, test := range tests {
+		got, err := ParseDeviceType(test.in)
+		if err != nil {
+			t.Errorf("ParseDeviceType(%s): expected no error, found %s", test.in, err.Error())
+		}
+		if got != test.want {
+			t.Errorf("ParseDeviceType(%s) = %v, want %v", test.in, got, test.want)
+		}
+	}
+
+	if _, err := ParseDeviceType("toaster"); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestDeviceType_String(t *testing.T) {
+	if Chrome.String() != "Chrome" {
+		t.Errorf("String() = %s, want %s", Chrome.String(), "Chrome")
+	}
+	if DeviceType(42).String() != "42" {
+		t.Errorf("String() = %s, want %s", DeviceType(42).String(), "42")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// WebPushRegistrable devices carry the keys of a web push subscription,
+// used to encrypt the payload of their pushes.
+type WebPushRegistrable interface {
+	WebPushPublicKey() string
+	WebPushAuthSecret() string
+}
+
+// WebPushDevice is a browser subscribed to web push. Its push token is the
+// subscription endpoint.
+type WebPushDevice struct {
+	HardwareId string
+	Language   string
+	TimeZone   int
+	Type       DeviceType
+	Endpoint   string
+	PublicKey  string
+	AuthSecret string
+}
+
+// NewWebPushDevice builds a device from the JSON serialization of a browser
+// PushSubscription, as given by PushSubscription.toJSON(). The type must be
+// Chrome or Firefox.
+func NewWebPushDevice(hardwareId string, t DeviceType, subscription []byte) (WebPushDevice, error) {
+	var s struct {
+		Endpoint string `json:"endpoint"`
+		Keys     struct {
+			P256dh string `json:"p256dh"`
+			Auth   string `json:"auth"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(subscription, &s); err != nil {
+		return WebPushDevice{}, err
+	}
+	device := WebPushDevice{
+		HardwareId: hardwareId,
+		Type:       t,
+		Endpoint:   s.Endpoint,
+		PublicKey:  s.Keys.P256dh,
+		AuthSecret: s.Keys.Auth,
+	}
+	return device, checkDevice(device)
+}
+
+func (d WebPushDevice) DeviceId() string {
+	return d.HardwareId
+}
+
+func (d WebPushDevice) DeviceLanguage() string {
+	return d.Language
+}
+
+func (d WebPushDevice) DevicePushToken() string {
+	return d.Endpoint
+}
+
+func (d WebPushDevice) DeviceTimeZone() int {
+	return d.TimeZone
+}
+
+func (d WebPushDevice) DeviceType() DeviceType {
+	return d.Type
+}
+
+func (d WebPushDevice) WebPushPublicKey() string {
+	return d.PublicKey
+}
+
+func (d WebPushDevice) WebPushAuthSecret() string {
+	return d.AuthSecret
+}
+
+// checkWebPushKeys validates the subscription keys of a web push device,
+// only accepted for the VAPID browsers.
+func checkWebPushKeys(t DeviceType, device WebPushRegistrable) error {
+	if t != Chrome && t != Firefox {
+		return fmt.Errorf("Web push is not supported for %s devices", t)
+	}
+	if len(device.WebPushPublicKey()) <= 0 {
+		return errors.New("Web push public key is required")
+	}
+	if len(device.WebPushAuthSecret()) <= 0 {
+		return errors.New("Web push auth secret is required")
+	}
+	return nil
+}
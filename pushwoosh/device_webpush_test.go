@@ -0,0 +1,95 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const testPushSubscription = `{
+	"endpoint": "https://fcm.googleapis.com/fcm/send/abc",
+	"expirationTime": null,
+	"keys": {"p256dh": "testPublicKey", "auth": "testAuthSecret"}
+}`
+
+func TestNewWebPushDevice(t *testing.T) {
+	device, err := NewWebPushDevice("testHardwareId", Chrome, []byte(testPushSubscription))
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := WebPushDevice{
+		HardwareId: "testHardwareId",
+		Type:       Chrome,
+		Endpoint:   "https://fcm.googleapis.com/fcm/send/abc",
+		PublicKey:  "testPublicKey",
+		AuthSecret: "testAuthSecret",
+	}
+	if !reflect.DeepEqual(device, want) {
+		t.Errorf("Device = %v, want %v", device, want)
+	}
+}
+
+func TestNewWebPushDevice_invalidSubscription(t *testing.T) {
+	tests := []string{
+		`{`,
+		`{"endpoint": "https://fcm.googleapis.com/fcm/send/abc"}`,
+		`{"endpoint": "abc", "keys": {"p256dh": "testPublicKey", "auth": "testAuthSecret"}}`,
+	}
+	for _, subscription := range tests {
+		if _, err := NewWebPushDevice("testHardwareId", Chrome, []byte(subscription)); err == nil {
+			t.Errorf("NewWebPushDevice(%s): expected an error", subscription)
+		}
+	}
+}
+
+func TestNewWebPushDevice_invalidType(t *testing.T) {
+	for _, deviceType := range []DeviceType{IOS, Safari, Android} {
+		if _, err := NewWebPushDevice("testHardwareId", deviceType, []byte(testPushSubscription)); err == nil {
+			t.Errorf("NewWebPushDevice(%s): expected an error", deviceType)
+		}
+	}
+}
+
+func TestDevicesService_Register_webPush(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/registerDevice", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"application": "testAppToken",
+			"hwid":        "testHardwareId",
+			"push_token":  "https://fcm.googleapis.com/fcm/send/abc",
+			"device_type": 11.0,
+			"language":    "de",
+			"public_key":  "testPublicKey",
+			"auth_token":  "testAuthSecret",
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	device, _ := NewWebPushDevice("testHardwareId", Chrome, []byte(testPushSubscription))
+	device.Language = "de"
+	client.Application = "testAppToken"
+	_, err := client.Devices.Register(device)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
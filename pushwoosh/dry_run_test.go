@@ -0,0 +1,74 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestClient_DryRun(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request to be sent, found %s", r.URL.Path)
+	})
+
+	var sent []DryRunRequest
+	client.DryRun = true
+	client.DryRunSink = func(r DryRunRequest) {
+		sent = append(sent, r)
+	}
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+
+	device := deviceTagsTest{HardwareId: "testHardwareId", Foo: 14, Bar: "fourteen"}
+	resp, err := client.Devices.SetTags(device)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if resp.Status != 200 {
+		t.Errorf("Status = %d, want %d", resp.Status, 200)
+	}
+	if _, err := client.Messages.Create(Notification{Content: Text("Hello")}); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("Sent = %d, want %d", len(sent), 2)
+	}
+	want := `{"request":{"application":"testAppToken","hwid":"testHardwareId","tags":{"bar":"fourteen","foo!":14}}}`
+	if sent[0].Method != "POST" || sent[0].Endpoint != "/setTags" || string(sent[0].Body) != want {
+		t.Errorf("Request = %s %s %s, want POST /setTags %s", sent[0].Method, sent[0].Endpoint, sent[0].Body, want)
+	}
+	if sent[1].Endpoint != "/createMessage" {
+		t.Errorf("Endpoint = %s, want %s", sent[1].Endpoint, "/createMessage")
+	}
+
+	body, _ := resp.Response.Response.Request.GetBody()
+	b, _ := ioutil.ReadAll(body)
+	if string(b) != want+"\n" {
+		t.Errorf("Body = %s, want %s", b, want)
+	}
+}
+
+func TestClient_DryRun_validation(t *testing.T) {
+	client := NewClient(nil)
+	client.DryRun = true
+	client.DryRunSink = func(r DryRunRequest) {
+		t.Errorf("Expected no request to be built, found %s", r.Endpoint)
+	}
+	client.Application = "testAppToken"
+
+	if _, err := client.Devices.Register(Device{HardwareId: "testHardwareId", Type: IOS}); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if _, err := client.Messages.Create(Notification{}); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+type Email struct {
+	Address  string
+	Language string
+	TimeZone int
+	UserId   string
+}
+
+type EmailRegistrable interface {
+	EmailAddress() string
+}
+
+type UserRegistrable interface {
+	DeviceUserId() string
+}
+
+func (e Email) EmailAddress() string {
+	return e.Address
+}
+
+func (e Email) DeviceLanguage() string {
+	return e.Language
+}
+
+func (e Email) DeviceTimeZone() int {
+	return e.TimeZone
+}
+
+func (e Email) DeviceUserId() string {
+	return e.UserId
+}
+
+// EmailNotification is an email to be sent by EmailsService. Subject and
+// Content, the HTML body, can be translated to several languages.
+type EmailNotification struct {
+	SendDate           string    `json:"send_date"`
+	IgnoreUserTimezone bool      `json:"ignore_user_timezone,omitempty"`
+	Subject            Localized `json:"subject"`
+	Content            Localized `json:"content"`
+	Emails             []string  `json:"devices,omitempty"`
+	Filter             string    `json:"filter,omitempty"`
+}
+
+type EmailsService struct {
+	client *Client
+}
+
+func (s EmailsService) Register(email EmailRegistrable) (*Response, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(email.EmailAddress()) <= 0 {
+		return nil, errors.New("Email address is required")
+	}
+	body := newRegisterEmailBody(s.client.Application, email)
+	req, err := s.client.NewRequest("POST", "/registerEmail", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// SetTags sets the email tags, taken from the struct fields with a "tag"
+// key, as DevicesService.SetTags does.
+func (s EmailsService) SetTags(email EmailRegistrable) (*TagsResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(email.EmailAddress()) <= 0 {
+		return nil, errors.New("Email address is required")
+	}
+	tags, err := getTags(email)
+	if err != nil {
+		return nil, err
+	}
+	body := struct {
+		Application string      `json:"application"`
+		Email       string      `json:"email"`
+		Tags        interface{} `json:"tags"`
+	}{s.client.Application, email.EmailAddress(), tags}
+	req, err := s.client.NewRequest("POST", "/setEmailTags", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(TagsResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s EmailsService) CreateMessage(notifications ...EmailNotification) (*MessagesResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(notifications) <= 0 {
+		return nil, errors.New("Notifications are required")
+	}
+	notifications = append([]EmailNotification(nil), notifications...)
+	for i := range notifications {
+		if err := checkEmailNotification(&notifications[i]); err != nil {
+			return nil, err
+		}
+	}
+	body := struct {
+		Application   string              `json:"application"`
+		Notifications []EmailNotification `json:"notifications"`
+	}{s.client.Application, notifications}
+	req, err := s.client.NewAuthRequest("POST", "/createEmailMessage", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(MessagesResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func checkEmailNotification(n *EmailNotification) error {
+	if len(n.Subject) <= 0 {
+		return errors.New("Email subject is required")
+	}
+	if len(n.Content) <= 0 {
+		return errors.New("Email content is required")
+	}
+	if len(n.SendDate) <= 0 {
+		n.SendDate = "now"
+	}
+	return nil
+}
+
+func newRegisterEmailBody(app string, email EmailRegistrable) interface{} {
+	body := struct {
+		Application string `json:"application"`
+		Email       string `json:"email"`
+		Language    string `json:"language,omitempty"`
+		TimeZone    int    `json:"tz_offset,omitempty"`
+		UserId      string `json:"userId,omitempty"`
+	}{}
+	body.Application = app
+	body.Email = email.EmailAddress()
+	timeZoneAspect, ok := email.(TimeZoneRegistrable)
+	if ok {
+		body.TimeZone = timeZoneAspect.DeviceTimeZone()
+	}
+	langugageAspect, ok := email.(LanguageRegistrable)
+	if ok {
+		body.Language = langugageAspect.DeviceLanguage()
+	}
+	userAspect, ok := email.(UserRegistrable)
+	if ok {
+		body.UserId = userAspect.DeviceUserId()
+	}
+	return body
+}
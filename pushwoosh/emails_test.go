@@ -0,0 +1,167 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestEmailsService_Register(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	email := Email{
+		Address:  "alice@example.com",
+		Language: "en",
+		TimeZone: 3600,
+		UserId:   "alice",
+	}
+
+	mux.HandleFunc("/registerEmail", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"application": "testAppToken",
+			"email":       "alice@example.com",
+			"language":    "en",
+			"tz_offset":   3600.0,
+			"userId":      "alice",
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	_, err := client.Emails.Register(email)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestEmailsService_Register_aspects(t *testing.T) {
+	body := newRegisterEmailBody("testAppToken", emailTagsTest{Address: "alice@example.com"})
+	b, _ := json.Marshal(body)
+	want := `{"application":"testAppToken","email":"alice@example.com"}`
+	if string(b) != want {
+		t.Errorf("Body = %s, want %s", b, want)
+	}
+}
+
+func TestEmailsService_Register_invalidAddress(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	_, err := client.Emails.Register(Email{Language: "en"})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestEmailsService_SetTags(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/setEmailTags", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Email string                 `json:"email"`
+				Tags  map[string]interface{} `json:"tags"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if body.Request.Email != "alice@example.com" {
+			t.Errorf("Email = %s, want %s", body.Request.Email, "alice@example.com")
+		}
+		want := map[string]interface{}{"Plan": "premium"}
+		if !reflect.DeepEqual(body.Request.Tags, want) {
+			t.Errorf("Tags = %v, want %v", body.Request.Tags, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	_, err := client.Emails.SetTags(emailTagsTest{Address: "alice@example.com", Plan: "premium"})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestEmailsService_CreateMessage(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createEmailMessage", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Auth          string                   `json:"auth"`
+				Application   string                   `json:"application"`
+				Notifications []map[string]interface{} `json:"notifications"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if body.Request.Auth != "testAuthToken" {
+			t.Errorf("Auth = %s, want %s", body.Request.Auth, "testAuthToken")
+		}
+		want := []map[string]interface{}{{
+			"send_date": "now",
+			"subject":   map[string]interface{}{"en": "Hello", "de": "Hallo"},
+			"content":   "<p>Hi</p>",
+			"devices":   []interface{}{"alice@example.com"},
+		}}
+		if !reflect.DeepEqual(body.Request.Notifications, want) {
+			t.Errorf("Notifications = %v, want %v", body.Request.Notifications, want)
+		}
+
+		var res MessagesResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Messages = []string{"testMessageCode"}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Emails.CreateMessage(EmailNotification{
+		Subject: Localized{"en": "Hello", "de": "Hallo"},
+		Content: Text("<p>Hi</p>"),
+		Emails:  []string{"alice@example.com"},
+	})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestEmailsService_CreateMessage_invalidSubject(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Emails.CreateMessage(EmailNotification{Content: Text("<p>Hi</p>")})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+type emailTagsTest struct {
+	Address string
+	Plan    string `tag:"Plan"`
+}
+
+func (email emailTagsTest) EmailAddress() string {
+	return email.Address
+}
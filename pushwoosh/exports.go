@@ -0,0 +1,371 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json_each_line"
+)
+
+// DefaultExportPollInterval is used by ExportsService.Wait when no interval
+// is given.
+const DefaultExportPollInterval = 5 * time.Second
+
+// ExportedDevice is a subscriber row of an export file. It can be used
+// anywhere a Device is accepted.
+type ExportedDevice struct {
+	HardwareId string
+	Language   string
+	PushToken  string
+	Type       DeviceType
+	Tags       map[string]interface{}
+}
+
+func (d ExportedDevice) DeviceId() string {
+	return d.HardwareId
+}
+
+func (d ExportedDevice) DeviceLanguage() string {
+	return d.Language
+}
+
+func (d ExportedDevice) DevicePushToken() string {
+	return d.PushToken
+}
+
+func (d ExportedDevice) DeviceType() DeviceType {
+	return d.Type
+}
+
+type ExportResponse struct {
+	Response
+	Info struct {
+		RequestId string `json:"request_id"`
+	} `json:"response,omitempty"`
+
+	format    ExportFormat
+	resultURL string
+}
+
+type ExportResultResponse struct {
+	Response
+	Info struct {
+		Link   string       `json:"link,omitempty"`
+		Format ExportFormat `json:"format,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+// Ready reports whether the export file is available for download.
+func (r ExportResultResponse) Ready() bool {
+	return len(r.Info.Link) > 0
+}
+
+type ExportsService struct {
+	client *Client
+}
+
+// Segment requests an export of every subscriber matching the given filter
+// expression.
+func (s ExportsService) Segment(filter string, format ExportFormat) (*ExportResponse, error) {
+	if len(filter) <= 0 {
+		return nil, errors.New("Devices filter is required")
+	}
+	if err := checkExportFormat(format); err != nil {
+		return nil, err
+	}
+	body := struct {
+		DevicesFilter string       `json:"devices_filter"`
+		Format        ExportFormat `json:"export_format"`
+	}{filter, format}
+	return s.export("/exportSegment", body, format)
+}
+
+// Subscribers requests an export of every subscriber of the application.
+func (s ExportsService) Subscribers(format ExportFormat) (*ExportResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if err := checkExportFormat(format); err != nil {
+		return nil, err
+	}
+	body := struct {
+		Application string       `json:"application"`
+		Format      ExportFormat `json:"export_format"`
+	}{s.client.Application, format}
+	return s.export("/exportSubscribers", body, format)
+}
+
+// Result checks the state of a previously requested export.
+func (s ExportsService) Result(export *ExportResponse) (*ExportResultResponse, error) {
+	if export == nil || len(export.Info.RequestId) <= 0 {
+		return nil, errors.New("Export request ID is required")
+	}
+	body := struct {
+		RequestId string `json:"request_id"`
+	}{export.Info.RequestId}
+	req, err := s.client.NewAuthRequest("POST", export.resultURL, body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(ExportResultResponse)
+	err = s.client.Do(req, resp)
+	if err == nil && len(resp.Info.Format) <= 0 {
+		resp.Info.Format = export.format
+	}
+	return resp, err
+}
+
+// Wait polls the export result every interval until the download link is
+// available or ctx, or the client context when nil, is done. In dry-run
+// mode it returns the first result, as no link ever becomes available.
+func (s ExportsService) Wait(ctx context.Context, export *ExportResponse, interval time.Duration) (*ExportResultResponse, error) {
+	if interval <= 0 {
+		interval = DefaultExportPollInterval
+	}
+	ctx = s.client.requestContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, err := s.Result(export)
+		if err != nil || resp.Ready() || s.client.DryRun {
+			return resp, err
+		}
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Download fetches the export file and returns an iterator over its rows.
+// The file is decoded as it is read, so it is never held in memory as a
+// whole. The iterator must be closed once done. The file is requested with
+// ctx, or with the client context when nil. Downloads fail in dry-run mode.
+// As they are not API calls, downloads are not logged or observed.
+func (s ExportsService) Download(ctx context.Context, result *ExportResultResponse) (*ExportIterator, error) {
+	if s.client.DryRun {
+		return nil, errors.New("Export download is not available in dry-run mode")
+	}
+	if result == nil || !result.Ready() {
+		return nil, errors.New("Export link is required")
+	}
+	req, err := http.NewRequest("GET", result.Info.Link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", s.client.UserAgent)
+	resp, err := s.client.send(req.WithContext(s.client.requestContext(ctx)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		closeBody(resp.Body)
+		return nil, errors.New(resp.Status)
+	}
+	it := NewExportIterator(resp.Body, result.Info.Format)
+	it.closer = resp.Body
+	return it, nil
+}
+
+func (s ExportsService) export(urlStr string, body interface{}, format ExportFormat) (*ExportResponse, error) {
+	req, err := s.client.NewAuthRequest("POST", urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(ExportResponse)
+	resp.format = format
+	resp.resultURL = urlStr + "/result"
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func checkExportFormat(format ExportFormat) error {
+	if format != ExportCSV && format != ExportJSON {
+		return fmt.Errorf("Unknown export format %q", format)
+	}
+	return nil
+}
+
+// ExportIterator decodes export rows one at a time:
+//
+//	for it.Next() {
+//		device := it.Device()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ExportIterator struct {
+	closer io.Closer
+	device ExportedDevice
+	err    error
+
+	csv    *csv.Reader
+	header []string
+	json   *json.Decoder
+}
+
+// NewExportIterator returns an iterator decoding rows of the given format
+// from r.
+func NewExportIterator(r io.Reader, format ExportFormat) *ExportIterator {
+	it := new(ExportIterator)
+	switch format {
+	case ExportCSV:
+		it.csv = csv.NewReader(bufio.NewReader(r))
+		it.csv.FieldsPerRecord = -1
+	case ExportJSON:
+		it.json = json.NewDecoder(bufio.NewReader(r))
+		it.json.UseNumber()
+	default:
+		it.err = checkExportFormat(format)
+	}
+	return it
+}
+
+// Next advances to the next row. It returns false at the end of the file or
+// on the first error.
+func (it *ExportIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	var err error
+	if it.csv != nil {
+		err = it.nextCSV()
+	} else {
+		err = it.nextJSON()
+	}
+	if err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+		return false
+	}
+	return true
+}
+
+// Device returns the row decoded by the last call to Next.
+func (it *ExportIterator) Device() ExportedDevice {
+	return it.device
+}
+
+// Err returns the first decoding error found, if any.
+func (it *ExportIterator) Err() error {
+	return it.err
+}
+
+func (it *ExportIterator) Close() error {
+	if it.closer != nil {
+		return it.closer.Close()
+	}
+	return nil
+}
+
+func (it *ExportIterator) nextCSV() error {
+	if it.header == nil {
+		header, err := it.csv.Read()
+		if err != nil {
+			return err
+		}
+		it.header = header
+	}
+	record, err := it.csv.Read()
+	if err != nil {
+		return err
+	}
+	device := ExportedDevice{Tags: map[string]interface{}{}}
+	for i, value := range record {
+		if i >= len(it.header) {
+			break
+		}
+		name := it.header[i]
+		switch normalizeExportColumn(name) {
+		case "hwid":
+			device.HardwareId = value
+		case "push_token":
+			device.PushToken = value
+		case "language":
+			device.Language = value
+		case "type":
+			device.Type, err = parseExportDeviceType(value)
+			if err != nil {
+				return err
+			}
+		default:
+			if len(value) > 0 {
+				device.Tags[name] = value
+			}
+		}
+	}
+	it.device = device
+	return nil
+}
+
+func (it *ExportIterator) nextJSON() error {
+	var row struct {
+		HardwareId string                 `json:"hwid"`
+		Language   string                 `json:"language"`
+		PushToken  string                 `json:"push_token"`
+		Type       json.Number            `json:"type"`
+		Tags       map[string]interface{} `json:"tags"`
+	}
+	if err := it.json.Decode(&row); err != nil {
+		return err
+	}
+	deviceType, err := parseExportDeviceType(row.Type.String())
+	if err != nil {
+		return err
+	}
+	if row.Tags == nil {
+		row.Tags = map[string]interface{}{}
+	}
+	it.device = ExportedDevice{
+		HardwareId: row.HardwareId,
+		Language:   row.Language,
+		PushToken:  row.PushToken,
+		Type:       deviceType,
+		Tags:       row.Tags,
+	}
+	return nil
+}
+
+func normalizeExportColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Replace(name, " ", "_", -1)
+	switch name {
+	case "hwid", "hardware_id":
+		return "hwid"
+	case "push_token", "token":
+		return "push_token"
+	case "type", "device_type", "platform":
+		return "type"
+	case "language", "lang":
+		return "language"
+	}
+	return name
+}
+
+func parseExportDeviceType(value string) (DeviceType, error) {
+	if len(strings.TrimSpace(value)) <= 0 {
+		return 0, nil
+	}
+	return ParseDeviceType(value)
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportsService_Segment(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/exportSegment", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Auth          string `json:"auth"`
+				DevicesFilter string `json:"devices_filter"`
+				Format        string `json:"export_format"`
+			} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		if body.Request.Auth != "testAuthToken" {
+			t.Errorf("Auth = %s, want %s", body.Request.Auth, "testAuthToken")
+		}
+
+		if body.Request.DevicesFilter != `A("testAppToken")` {
+			t.Errorf("DevicesFilter = %s, want %s", body.Request.DevicesFilter, `A("testAppToken")`)
+		}
+
+		if body.Request.Format != "csv" {
+			t.Errorf("Format = %s, want %s", body.Request.Format, "csv")
+		}
+
+		var res ExportResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.RequestId = "testRequestId"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Exports.Segment(`A("testAppToken")`, ExportCSV)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.RequestId != "testRequestId" {
+		t.Errorf("RequestId = %s, want %s", resp.Info.RequestId, "testRequestId")
+	}
+}
+
+func TestExportsService_Segment_invalidAuth(t *testing.T) {
+	client := NewClient(nil)
+	_, err := client.Exports.Segment(`A("testAppToken")`, ExportCSV)
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestExportsService_Subscribers_invalidFormat(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Exports.Subscribers("xml")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestExportsService_WaitAndDownload(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/exportSubscribers", func(w http.ResponseWriter, r *http.Request) {
+		var res ExportResponse
+		res.Status = 200
+		res.Info.RequestId = "testRequestId"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	polls := 0
+	mux.HandleFunc("/exportSubscribers/result", func(w http.ResponseWriter, r *http.Request) {
+		var res ExportResultResponse
+		res.Status = 200
+		polls++
+		if polls > 1 {
+			res.Info.Link = server.URL + "/download"
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Hwid,Push Token,Type,Language,City\n")
+		fmt.Fprint(w, "hwid1,token1,1,en,Berlin\n")
+		fmt.Fprint(w, "hwid2,token2,Android,es,\n")
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	export, err := client.Exports.Subscribers(ExportCSV)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	result, err := client.Exports.Wait(context.Background(), export, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if polls != 2 {
+		t.Errorf("Polls = %d, want %d", polls, 2)
+	}
+
+	it, err := client.Exports.Download(context.Background(), result)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	defer it.Close()
+
+	var devices []ExportedDevice
+	for it.Next() {
+		devices = append(devices, it.Device())
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := []ExportedDevice{
+		{"hwid1", "en", "token1", IOS, map[string]interface{}{"City": "Berlin"}},
+		{"hwid2", "es", "token2", Android, map[string]interface{}{}},
+	}
+	if !reflect.DeepEqual(devices, want) {
+		t.Errorf("Devices = %v, want %v", devices, want)
+	}
+}
+
+func TestExportsService_Wait_canceled(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/exportSegment/result", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Status: 200})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.AuthToken = "testAuthToken"
+	export := &ExportResponse{resultURL: "/exportSegment/result"}
+	export.Info.RequestId = "testRequestId"
+	_, err := client.Exports.Wait(ctx, export, time.Hour)
+	if err != context.Canceled {
+		t.Errorf("Error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestExportsService_clientContext(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/exportSegment/result", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Status: 200})
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client.AuthToken = "testAuthToken"
+	export := &ExportResponse{resultURL: "/exportSegment/result"}
+	export.Info.RequestId = "testRequestId"
+	_, err := client.WithContext(ctx).Exports.Wait(nil, export, time.Hour)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	result := new(ExportResultResponse)
+	result.Info.Link = server.URL + "/download"
+	if _, err := client.WithContext(ctx).Exports.Download(nil, result); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestExportsService_dryRun(t *testing.T) {
+	client := NewClient(nil)
+	client.DryRun = true
+	client.AuthToken = "testAuthToken"
+
+	export := &ExportResponse{resultURL: "/exportSegment/result"}
+	export.Info.RequestId = "testRequestId"
+	result, err := client.Exports.Wait(context.Background(), export, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if result.Ready() {
+		t.Errorf("Expected no download link")
+	}
+
+	result.Info.Link = "https://example.com/export.csv"
+	if _, err := client.Exports.Download(context.Background(), result); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestNewExportIterator_json(t *testing.T) {
+	input := `{"hwid":"hwid1","push_token":"token1","type":3,"language":"en","tags":{"City":"Berlin"}}
+{"hwid":"hwid2","push_token":"token2","type":7}
+`
+	it := NewExportIterator(strings.NewReader(input), ExportJSON)
+	var devices []ExportedDevice
+	for it.Next() {
+		devices = append(devices, it.Device())
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := []ExportedDevice{
+		{"hwid1", "en", "token1", Android, map[string]interface{}{"City": "Berlin"}},
+		{"hwid2", "", "token2", OSX, map[string]interface{}{}},
+	}
+	if !reflect.DeepEqual(devices, want) {
+		t.Errorf("Devices = %v, want %v", devices, want)
+	}
+}
+
+func TestNewExportIterator_invalidRow(t *testing.T) {
+	input := "hwid,type\nhwid1,toaster\nhwid2,1\n"
+	it := NewExportIterator(strings.NewReader(input), ExportCSV)
+	if it.Next() {
+		t.Errorf("Expected no rows")
+	}
+	if it.Err() == nil {
+		t.Errorf("Expected an error")
+	}
+}
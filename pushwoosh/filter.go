@@ -0,0 +1,554 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a node of a Pushwoosh filter expression. Its String method
+// renders the expression as accepted by the API, e.g.
+//
+//	And(T("City", EQ, "Berlin"), A("XXXXX-XXXXX")).String()
+//
+// renders
+//
+//	T("City", EQ, "Berlin") * A("XXXXX-XXXXX")
+type Filter interface {
+	String() string
+}
+
+type TagOperator string
+
+const (
+	EQ      TagOperator = "EQ"
+	NOTEQ   TagOperator = "NOTEQ"
+	IN      TagOperator = "IN"
+	NOTIN   TagOperator = "NOTIN"
+	GTE     TagOperator = "GTE"
+	LTE     TagOperator = "LTE"
+	BETWEEN TagOperator = "BETWEEN"
+	NOTSET  TagOperator = "NOTSET"
+	ANY     TagOperator = "ANY"
+)
+
+// ApplicationFilter matches the subscribers of an application, optionally
+// restricted to some platforms ("iOS", "Android", ...).
+type ApplicationFilter struct {
+	Code      string
+	Platforms []string
+}
+
+// TagFilter matches the subscribers whose tag satisfies the operator. When
+// Application is set, the tag is looked up as an application specific tag.
+type TagFilter struct {
+	Application string
+	Name        string
+	Operator    TagOperator
+	Values      []interface{}
+}
+
+// GeoFilter matches the subscribers within Radius meters of a location.
+type GeoFilter struct {
+	Lat    float64
+	Lng    float64
+	Radius int
+}
+
+// AndFilter is the intersection of its filters.
+type AndFilter []Filter
+
+// OrFilter is the union of its filters.
+type OrFilter []Filter
+
+// ExceptFilter matches the subscribers of Filter not matched by Except.
+type ExceptFilter struct {
+	Filter Filter
+	Except Filter
+}
+
+func A(code string, platforms ...string) ApplicationFilter {
+	return ApplicationFilter{code, platforms}
+}
+
+// T builds a tag filter. NOTSET and ANY take no values, IN, NOTIN and
+// BETWEEN take a list of values and the rest of operators a single one.
+func T(name string, op TagOperator, values ...interface{}) TagFilter {
+	return TagFilter{Name: name, Operator: op, Values: values}
+}
+
+func AT(app, name string, op TagOperator, values ...interface{}) TagFilter {
+	return TagFilter{app, name, op, values}
+}
+
+func G(lat, lng float64, radius int) GeoFilter {
+	return GeoFilter{lat, lng, radius}
+}
+
+func And(filters ...Filter) AndFilter {
+	return AndFilter(filters)
+}
+
+func Or(filters ...Filter) OrFilter {
+	return OrFilter(filters)
+}
+
+// AndNot matches the subscribers of filter not matched by not. The filter
+// language has no unary negation, so a negated filter always needs a set to
+// be taken from.
+func AndNot(filter, not Filter) ExceptFilter {
+	return ExceptFilter{filter, not}
+}
+
+func (f ApplicationFilter) String() string {
+	if len(f.Platforms) <= 0 {
+		return fmt.Sprintf("A(%s)", quoteFilterString(f.Code))
+	}
+	platforms := make([]interface{}, len(f.Platforms))
+	for i, p := range f.Platforms {
+		platforms[i] = p
+	}
+	return fmt.Sprintf("A(%s, %s)", quoteFilterString(f.Code), formatFilterList(platforms))
+}
+
+func (f TagFilter) String() string {
+	var buffer bytes.Buffer
+	if len(f.Application) > 0 {
+		fmt.Fprintf(&buffer, "AT(%s, ", quoteFilterString(f.Application))
+	} else {
+		buffer.WriteString("T(")
+	}
+	fmt.Fprintf(&buffer, "%s, %s", quoteFilterString(f.Name), f.Operator)
+	switch f.Operator {
+	case NOTSET, ANY:
+	case IN, NOTIN, BETWEEN:
+		fmt.Fprintf(&buffer, ", %s", formatFilterList(f.Values))
+	default:
+		if len(f.Values) == 1 {
+			fmt.Fprintf(&buffer, ", %s", formatFilterValue(f.Values[0]))
+		} else {
+			fmt.Fprintf(&buffer, ", %s", formatFilterList(f.Values))
+		}
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
+
+func (f GeoFilter) String() string {
+	return fmt.Sprintf("G(%s, %s, %d)", formatFilterValue(f.Lat), formatFilterValue(f.Lng), f.Radius)
+}
+
+func (f AndFilter) String() string {
+	return joinFilters(f, f, " * ")
+}
+
+func (f OrFilter) String() string {
+	return joinFilters(f, f, " + ")
+}
+
+func (f ExceptFilter) String() string {
+	left := formatFilterOperand(f, f.Filter)
+	right := f.Except.String()
+	switch f.Except.(type) {
+	case OrFilter, ExceptFilter:
+		right = "(" + right + ")"
+	}
+	return left + ` \ ` + right
+}
+
+func joinFilters(parent Filter, filters []Filter, sep string) string {
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		parts[i] = formatFilterOperand(parent, f)
+	}
+	return strings.Join(parts, sep)
+}
+
+// formatFilterOperand renders f, wrapping it in parentheses when it would
+// otherwise bind differently inside parent.
+func formatFilterOperand(parent, f Filter) string {
+	switch f.(type) {
+	case OrFilter:
+		if _, ok := parent.(OrFilter); !ok {
+			return "(" + f.String() + ")"
+		}
+	case ExceptFilter:
+		if _, ok := parent.(ExceptFilter); !ok {
+			return "(" + f.String() + ")"
+		}
+	}
+	return f.String()
+}
+
+func formatFilterList(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatFilterValue(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func formatFilterValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return quoteFilterString(value)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		return value.String()
+	}
+	return fmt.Sprint(v)
+}
+
+func quoteFilterString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// ParseFilter parses a filter expression, as returned by the API, back into
+// a Filter. Parsing the rendering of a parsed filter gives the same filter.
+func ParseFilter(expr string) (Filter, error) {
+	p := &filterParser{input: expr}
+	p.next()
+	f, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != filterEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return f, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterEOF filterTokenKind = iota
+	filterIdent
+	filterString
+	filterNumber
+	filterSymbol
+	filterInvalid
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	text  string
+	value interface{}
+	pos   int
+}
+
+func (t filterToken) String() string {
+	if t.kind == filterEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+type filterParser struct {
+	input string
+	pos   int
+	tok   filterToken
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Invalid filter at position %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = filterToken{kind: filterEOF, pos: start}
+		return
+	}
+	c := p.input[p.pos]
+	switch {
+	case c == '"':
+		dec := json.NewDecoder(strings.NewReader(p.input[start:]))
+		var s string
+		if err := dec.Decode(&s); err != nil {
+			p.tok = filterToken{kind: filterInvalid, text: p.input[start:], pos: start}
+			p.pos = len(p.input)
+			return
+		}
+		p.pos = start + int(dec.InputOffset())
+		p.tok = filterToken{filterString, p.input[start:p.pos], s, start}
+	case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		p.pos++
+		for p.pos < len(p.input) && strings.IndexByte("0123456789.eE+-", p.input[p.pos]) >= 0 {
+			p.pos++
+		}
+		text := p.input[start:p.pos]
+		var value interface{}
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			value = n
+		} else if f, err := strconv.ParseFloat(text, 64); err == nil {
+			value = f
+		} else {
+			p.tok = filterToken{kind: filterInvalid, text: text, pos: start}
+			return
+		}
+		p.tok = filterToken{filterNumber, text, value, start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (p.input[p.pos] == '_' || unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		p.tok = filterToken{kind: filterIdent, text: p.input[start:p.pos], pos: start}
+	default:
+		p.pos++
+		p.tok = filterToken{kind: filterSymbol, text: p.input[start:p.pos], pos: start}
+	}
+}
+
+func (p *filterParser) expect(symbol string) error {
+	if p.tok.kind != filterSymbol || p.tok.text != symbol {
+		return p.errorf("expected %q, found %s", symbol, p.tok)
+	}
+	p.next()
+	return nil
+}
+
+func (p *filterParser) accept(symbol string) bool {
+	if p.tok.kind == filterSymbol && p.tok.text == symbol {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// parseExpr parses unions and differences, which bind looser than
+// intersections and associate to the left.
+func (p *filterParser) parseExpr() (Filter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("+"):
+			right, err := p.parseTerm()
+			if err != nil {
+				return nil, err
+			}
+			if or, ok := left.(OrFilter); ok {
+				left = append(or, right)
+			} else {
+				left = Or(left, right)
+			}
+		case p.accept(`\`):
+			right, err := p.parseTerm()
+			if err != nil {
+				return nil, err
+			}
+			left = AndNot(left, right)
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *filterParser) parseTerm() (Filter, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("*") {
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		if and, ok := left.(AndFilter); ok {
+			left = append(and, right)
+		} else {
+			left = And(left, right)
+		}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseFactor() (Filter, error) {
+	if p.accept("(") {
+		f, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	if p.tok.kind != filterIdent {
+		return nil, p.errorf("expected a filter, found %s", p.tok)
+	}
+	name := p.tok.text
+	p.next()
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	return newFilterFromCall(p, name, args)
+}
+
+func (p *filterParser) parseArgs() ([]interface{}, error) {
+	var args []interface{}
+	if p.accept(")") {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.accept(")") {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *filterParser) parseArg() (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case filterString, filterNumber:
+		p.next()
+		return tok.value, nil
+	case filterIdent:
+		p.next()
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return TagOperator(tok.text), nil
+	}
+	if p.accept("[") {
+		list := []interface{}{}
+		if p.accept("]") {
+			return list, nil
+		}
+		for {
+			arg, err := p.parseArg()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, arg)
+			if p.accept("]") {
+				return list, nil
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, p.errorf("expected an argument, found %s", tok)
+}
+
+func newFilterFromCall(p *filterParser, name string, args []interface{}) (Filter, error) {
+	switch name {
+	case "A":
+		if len(args) < 1 || len(args) > 2 {
+			return nil, p.errorf("A takes 1 or 2 arguments, found %d", len(args))
+		}
+		code, ok := args[0].(string)
+		if !ok {
+			return nil, p.errorf("A application must be a string")
+		}
+		f := A(code)
+		if len(args) == 2 {
+			list, ok := args[1].([]interface{})
+			if !ok {
+				return nil, p.errorf("A platforms must be a list")
+			}
+			for _, v := range list {
+				platform, ok := v.(string)
+				if !ok {
+					return nil, p.errorf("A platforms must be strings")
+				}
+				f.Platforms = append(f.Platforms, platform)
+			}
+		}
+		return f, nil
+	case "T", "AT":
+		var app string
+		if name == "AT" {
+			if len(args) < 1 {
+				return nil, p.errorf("AT application is required")
+			}
+			s, ok := args[0].(string)
+			if !ok {
+				return nil, p.errorf("AT application must be a string")
+			}
+			app, args = s, args[1:]
+		}
+		if len(args) < 2 || len(args) > 3 {
+			return nil, p.errorf("%s takes a tag, an operator and a value", name)
+		}
+		tag, ok := args[0].(string)
+		if !ok {
+			return nil, p.errorf("%s tag must be a string", name)
+		}
+		op, ok := args[1].(TagOperator)
+		if !ok {
+			return nil, p.errorf("%s operator must be an identifier", name)
+		}
+		f := AT(app, tag, op)
+		if len(args) == 3 {
+			switch op {
+			case IN, NOTIN, BETWEEN:
+				list, ok := args[2].([]interface{})
+				if !ok {
+					return nil, p.errorf("%s %s takes a list", name, op)
+				}
+				f.Values = list
+			default:
+				if list, ok := args[2].([]interface{}); ok {
+					f.Values = list
+				} else {
+					f.Values = []interface{}{args[2]}
+				}
+			}
+		}
+		return f, nil
+	case "G":
+		if len(args) != 3 {
+			return nil, p.errorf("G takes 3 arguments, found %d", len(args))
+		}
+		var coords [2]float64
+		for i := range coords {
+			switch n := args[i].(type) {
+			case int64:
+				coords[i] = float64(n)
+			case float64:
+				coords[i] = n
+			default:
+				return nil, p.errorf("G coordinates must be numbers")
+			}
+		}
+		radius, ok := args[2].(int64)
+		if !ok {
+			return nil, p.errorf("G radius must be an integer")
+		}
+		return G(coords[0], coords[1], int(radius)), nil
+	}
+	return nil, p.errorf("unknown filter %q", name)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilter_String(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{A("XXXXX-XXXXX"), `A("XXXXX-XXXXX")`},
+		{A("XXXXX-XXXXX", "iOS", "Android"), `A("XXXXX-XXXXX", ["iOS", "Android"])`},
+		{T("City", EQ, "Berlin"), `T("City", EQ, "Berlin")`},
+		{T("Age", BETWEEN, 18, 30), `T("Age", BETWEEN, [18, 30])`},
+		{T("City", NOTSET), `T("City", NOTSET)`},
+		{T("Score", GTE, 2.5), `T("Score", GTE, 2.5)`},
+		{AT("XXXXX-XXXXX", "Level", IN, 1, 2), `AT("XXXXX-XXXXX", "Level", IN, [1, 2])`},
+		{G(52.52, 13.405, 1000), `G(52.52, 13.405, 1000)`},
+		{
+			And(T("City", EQ, "Berlin"), A("app")),
+			`T("City", EQ, "Berlin") * A("app")`,
+		},
+		{
+			And(Or(A("a"), A("b")), T("City", EQ, "Berlin")),
+			`(A("a") + A("b")) * T("City", EQ, "Berlin")`,
+		},
+		{
+			Or(And(A("a"), T("x", ANY)), A("b")),
+			`A("a") * T("x", ANY) + A("b")`,
+		},
+		{
+			AndNot(A("a"), Or(T("x", EQ, 1), T("y", EQ, 2))),
+			`A("a") \ (T("x", EQ, 1) + T("y", EQ, 2))`,
+		},
+		{
+			AndNot(AndNot(A("a"), A("b")), A("c")),
+			`A("a") \ A("b") \ A("c")`,
+		},
+		{
+			AndNot(A("a"), AndNot(A("b"), A("c"))),
+			`A("a") \ (A("b") \ A("c"))`,
+		},
+		{
+			Or(A("a"), AndNot(A("b"), A("c"))),
+			`A("a") + (A("b") \ A("c"))`,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.filter.String(); got != test.want {
+			t.Errorf("String() = %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter(`T("City", EQ, "Berlin") * A("app", ["iOS"])`)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	want := And(
+		TagFilter{Name: "City", Operator: EQ, Values: []interface{}{"Berlin"}},
+		ApplicationFilter{"app", []string{"iOS"}},
+	)
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("Filter = %#v, want %#v", f, want)
+	}
+}
+
+func TestParseFilter_roundTrip(t *testing.T) {
+	exprs := []string{
+		`A("XXXXX-XXXXX")`,
+		`A("XXXXX-XXXXX", ["iOS", "Android"])`,
+		`T("City", EQ, "Ber\"lin")`,
+		`T("Age", BETWEEN, [18, 30])`,
+		`T("City", NOTSET)`,
+		`T("Score", LTE, -2.5)`,
+		`T("Premium", EQ, true)`,
+		`AT("XXXXX-XXXXX", "Level", NOTIN, [1, 2])`,
+		`G(52.52, 13.405, 1000)`,
+		`(A("a") + A("b")) * T("City", EQ, "Berlin")`,
+		`A("a") * T("x", ANY) + A("b")`,
+		`A("a") \ (T("x", EQ, 1) + T("y", EQ, 2))`,
+		`A("a") \ A("b") \ A("c")`,
+		`A("a") + (A("b") \ A("c"))`,
+	}
+
+	for _, expr := range exprs {
+		f, err := ParseFilter(expr)
+		if err != nil {
+			t.Errorf("ParseFilter(%s): expected no error, found %s", expr, err.Error())
+			continue
+		}
+		if got := f.String(); got != expr {
+			t.Errorf("String() = %s, want %s", got, expr)
+		}
+	}
+}
+
+func TestParseFilter_invalid(t *testing.T) {
+	exprs := []string{
+		``,
+		`A(`,
+		`A("a"`,
+		`A("a") *`,
+		`X("a")`,
+		`T("City", "EQ", "Berlin")`,
+		`T("City", IN, "Berlin")`,
+		`G(1, 2, 3.5)`,
+		`A("a") A("b")`,
+		`A("a) * A("b")`,
+	}
+
+	for _, expr := range exprs {
+		if _, err := ParseFilter(expr); err == nil {
+			t.Errorf("ParseFilter(%s): expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+// FilterInfo describes a segment stored in the Pushwoosh account.
+type FilterInfo struct {
+	Name           string `json:"name"`
+	Expression     string `json:"expression"`
+	ExpirationDate string `json:"expiration_date,omitempty"`
+}
+
+// Filter parses the segment expression.
+func (f FilterInfo) Filter() (Filter, error) {
+	return ParseFilter(f.Expression)
+}
+
+type FilterResponse struct {
+	Response
+	Info struct {
+		Name string `json:"name,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type FiltersResponse struct {
+	Response
+	Info struct {
+		Filters []FilterInfo `json:"filters,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type FiltersService struct {
+	client *Client
+}
+
+func (s FiltersService) Create(name string, filter Filter) (*FilterResponse, error) {
+	if len(name) <= 0 {
+		return nil, errors.New("Filter name is required")
+	}
+	if filter == nil {
+		return nil, errors.New("Filter expression is required")
+	}
+	body := struct {
+		Name       string `json:"name"`
+		Expression string `json:"filter_expression"`
+	}{name, filter.String()}
+	req, err := s.client.NewAuthRequest("POST", "/createFilter", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(FilterResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s FiltersService) List() (*FiltersResponse, error) {
+	req, err := s.client.NewAuthRequest("POST", "/listFilters", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	resp := new(FiltersResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s FiltersService) Delete(name string) (*Response, error) {
+	if len(name) <= 0 {
+		return nil, errors.New("Filter name is required")
+	}
+	body := struct {
+		Name string `json:"name"`
+	}{name}
+	req, err := s.client.NewAuthRequest("POST", "/deleteFilter", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
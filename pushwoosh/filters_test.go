@@ -0,0 +1,137 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestFiltersService_Create(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createFilter", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Auth       string `json:"auth"`
+				Name       string `json:"name"`
+				Expression string `json:"filter_expression"`
+			} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		if body.Request.Auth != "testAuthToken" {
+			t.Errorf("Auth = %s, want %s", body.Request.Auth, "testAuthToken")
+		}
+
+		if body.Request.Name != "berliners" {
+			t.Errorf("Name = %s, want %s", body.Request.Name, "berliners")
+		}
+
+		want := `T("City", EQ, "Berlin") * A("testAppToken")`
+		if body.Request.Expression != want {
+			t.Errorf("Expression = %s, want %s", body.Request.Expression, want)
+		}
+
+		var res FilterResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Name = "berliners"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	filter := And(T("City", EQ, "Berlin"), A("testAppToken"))
+	resp, err := client.Filters.Create("berliners", filter)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.Name != "berliners" {
+		t.Errorf("Name = %s, want %s", resp.Info.Name, "berliners")
+	}
+}
+
+func TestFiltersService_Create_invalidAuth(t *testing.T) {
+	client := NewClient(nil)
+	_, err := client.Filters.Create("berliners", A("testAppToken"))
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestFiltersService_Create_invalidFilter(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Filters.Create("berliners", nil)
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestFiltersService_List(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/listFilters", func(w http.ResponseWriter, r *http.Request) {
+		var res FiltersResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Filters = []FilterInfo{
+			{Name: "berliners", Expression: `T("City", EQ, "Berlin")`},
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Filters.List()
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if len(resp.Info.Filters) != 1 {
+		t.Fatalf("Filters = %v, want 1 filter", resp.Info.Filters)
+	}
+	f, err := resp.Info.Filters[0].Filter()
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if _, ok := f.(TagFilter); !ok {
+		t.Errorf("Filter = %#v, want a TagFilter", f)
+	}
+}
+
+func TestFiltersService_Delete(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/deleteFilter", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Name string `json:"name"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Request.Name != "berliners" {
+			t.Errorf("Name = %s, want %s", body.Request.Name, "berliners")
+		}
+		json.NewEncoder(w).Encode(Response{Status: 210, Message: "foo"})
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Filters.Delete("berliners")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+	want := Response{Status: 210, Message: "foo"}
+	if !compareResponses(*resp, want) {
+		t.Errorf("Response resp = %v, want %v", resp, want)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// Logger receives a record of every request made by a client. It is
+// satisfied by *slog.Logger. Successful requests are logged with Debug and
+// failed ones with Error, both with the key-value pairs:
+//
+//	endpoint     request path, e.g. "/registerDevice"
+//	duration     time.Duration
+//	http_status  HTTP status code, when a response was received
+//	api_status   Pushwoosh status code, when one was decoded
+//	body         request body, with secrets redacted
+//	error        error, for failed requests only
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// redactedFields are the body fields never written to logs or cassettes,
+// with the placeholder written instead. Large binary fields are omitted.
+var redactedFields = map[string]string{
+	"application":     redacted,
+	"auth":            redacted,
+	"auth_token":      redacted,
+	"devices":         redacted,
+	"email":           redacted,
+	"hwid":            redacted,
+	"key":             redacted,
+	"public_key":      redacted,
+	"push_token":      redacted,
+	"service_account": redacted,
+	"zip":             omitted,
+}
+
+const (
+	redacted = "[REDACTED]"
+	omitted  = "[OMITTED]"
+)
+
+func (c *Client) logRequest(req *http.Request, info RequestInfo) {
+	args := []interface{}{
+		"endpoint", info.Endpoint,
+		"duration", info.Duration,
+	}
+	if info.HTTPStatus != 0 {
+		args = append(args, "http_status", info.HTTPStatus)
+	}
+	if info.APIStatus != 0 {
+		args = append(args, "api_status", info.APIStatus)
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(body)
+			body.Close()
+			args = append(args, "body", string(redactBody(b)))
+		}
+	}
+	if info.Err != nil {
+		c.Logger.Error("pushwoosh request failed", append(args, "error", info.Err)...)
+		return
+	}
+	c.Logger.Debug("pushwoosh request", args...)
+}
+
+// redactBody replaces the values of redactedFields, at any depth, of a JSON
+// body. Bodies that are not JSON are dropped altogether.
+func redactBody(b []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		if len(b) <= 0 {
+			return b
+		}
+		return []byte(redacted)
+	}
+	out, _ := json.Marshal(redactValue(v))
+	return out
+}
+
+// redactValue replaces the values of redactedFields, at any depth, of a
+// decoded JSON value.
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, field := range value {
+			if placeholder, ok := redactedFields[k]; ok {
+				value[k] = placeholder
+			} else {
+				value[k] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+	return v
+}
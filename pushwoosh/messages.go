@@ -0,0 +1,198 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Localized is a text translated to several languages, keyed by language
+// code. A text with the empty language code only is sent as a plain string,
+// shown to every subscriber regardless of their language.
+type Localized map[string]string
+
+// Text returns a text shown to every subscriber.
+func Text(s string) Localized {
+	return Localized{"": s}
+}
+
+func (l Localized) MarshalJSON() ([]byte, error) {
+	if s, ok := l[""]; ok && len(l) == 1 {
+		return json.Marshal(s)
+	}
+	return json.Marshal(map[string]string(l))
+}
+
+func (l *Localized) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = Text(s)
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*l = Localized(m)
+	return nil
+}
+
+// Notification is a message to be sent by MessagesService. When Preset is
+// set, the fields of the notification override the ones of the preset.
+type Notification struct {
+	SendDate           string       `json:"send_date"`
+	IgnoreUserTimezone bool         `json:"ignore_user_timezone,omitempty"`
+	Content            Localized    `json:"content,omitempty"`
+	Data               interface{}  `json:"data,omitempty"`
+	Platforms          []DeviceType `json:"platforms,omitempty"`
+	Devices            []string     `json:"devices,omitempty"`
+	Filter             string       `json:"filter,omitempty"`
+	Link               string       `json:"link,omitempty"`
+	PageId             int          `json:"page_id,omitempty"`
+	RichMedia          string       `json:"rich_media,omitempty"`
+	Preset             string       `json:"preset,omitempty"`
+	Campaign           string       `json:"campaign,omitempty"`
+	// Inbox options. InboxDate is the day, in the "2006-01-02" layout, the
+	// message expires from the inbox.
+	ShowInInbox bool   `json:"show_in_inbox,omitempty"`
+	InboxImage  string `json:"inbox_image,omitempty"`
+	InboxDate   string `json:"inbox_date,omitempty"`
+}
+
+type MessagesResponse struct {
+	Response
+	Info struct {
+		Messages []string `json:"Messages,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type TargetedMessageResponse struct {
+	Response
+	Info struct {
+		MessageCode string `json:"messageCode,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type MessageStatsResponse struct {
+	Response
+	Info struct {
+		Rows []CampaignStat `json:"rows,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type MessagesService struct {
+	client *Client
+}
+
+// Create sends the notifications to the client application or, when it has
+// none, to its applications group. Every notification gets its own message
+// code, returned in the same order.
+func (s MessagesService) Create(notifications ...Notification) (*MessagesResponse, error) {
+	if len(s.client.Application) <= 0 && len(s.client.ApplicationsGroup) <= 0 {
+		return nil, errors.New("Application token or applications group is required")
+	}
+	if len(notifications) <= 0 {
+		return nil, errors.New("Notifications are required")
+	}
+	notifications = append([]Notification(nil), notifications...)
+	for i := range notifications {
+		if err := checkNotification(&notifications[i]); err != nil {
+			return nil, err
+		}
+	}
+	body := struct {
+		Application       string         `json:"application,omitempty"`
+		ApplicationsGroup string         `json:"applications_group,omitempty"`
+		Notifications     []Notification `json:"notifications"`
+	}{s.client.Application, "", notifications}
+	if len(body.Application) <= 0 {
+		body.ApplicationsGroup = s.client.ApplicationsGroup
+	}
+	req, err := s.client.NewAuthRequest("POST", "/createMessage", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(MessagesResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// CreateTargeted sends the notification to every subscriber matching the
+// filter. The filter is applied to the whole account, so it should usually
+// include the application, e.g.
+//
+//	And(A("XXXXX-XXXXX", "Android"), T("Country", EQ, "es"), T("cart_value", GTE, 50))
+func (s MessagesService) CreateTargeted(filter Filter, notification Notification) (*TargetedMessageResponse, error) {
+	if filter == nil {
+		return nil, errors.New("Devices filter is required")
+	}
+	if len(notification.Devices) > 0 || len(notification.Filter) > 0 {
+		return nil, errors.New("Targeted messages are addressed by devices filter only")
+	}
+	if err := checkNotification(&notification); err != nil {
+		return nil, err
+	}
+	body := struct {
+		DevicesFilter string `json:"devices_filter"`
+		Notification
+	}{filter.String(), notification}
+	req, err := s.client.NewAuthRequest("POST", "/createTargetedMessage", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(TargetedMessageResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// Delete cancels a scheduled message.
+func (s MessagesService) Delete(code string) (*Response, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Message code is required")
+	}
+	body := struct {
+		Message string `json:"message"`
+	}{code}
+	req, err := s.client.NewAuthRequest("POST", "/deleteMessage", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// Stats returns the statistics of a message.
+func (s MessagesService) Stats(code string) (*MessageStatsResponse, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Message code is required")
+	}
+	body := struct {
+		Message string `json:"message"`
+	}{code}
+	req, err := s.client.NewAuthRequest("POST", "/getMsgStats", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(MessageStatsResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// checkNotification validates n, filling the send date if missing.
+func checkNotification(n *Notification) error {
+	if len(n.Content) <= 0 && len(n.Preset) <= 0 {
+		return errors.New("Notification content or preset is required")
+	}
+	if !n.ShowInInbox && (len(n.InboxImage) > 0 || len(n.InboxDate) > 0) {
+		return errors.New("Inbox options require ShowInInbox")
+	}
+	if len(n.SendDate) <= 0 {
+		n.SendDate = "now"
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"context"
+	"errors"
+)
+
+// HistoryFilters narrows the messages returned by MessagesService.History.
+// Dates use the "2006-01-02 15:04:05" layout.
+type HistoryFilters struct {
+	Source string `json:"source,omitempty"`
+	// SearchBy is one of "notificationID", "notificationCode",
+	// "applicationCode" or "campaignCode", matched against Value.
+	SearchBy string `json:"searchBy,omitempty"`
+	Value    string `json:"value,omitempty"`
+	DateFrom string `json:"date_from,omitempty"`
+	DateTo   string `json:"date_to,omitempty"`
+	// Limit is the number of messages fetched per page.
+	Limit int `json:"limitMessages,omitempty"`
+}
+
+type HistoryEntry struct {
+	Id          int64        `json:"id"`
+	Code        string       `json:"code"`
+	Application string       `json:"application,omitempty"`
+	CreatedDate string       `json:"createdDate,omitempty"`
+	SendDate    string       `json:"sendDate"`
+	Status      string       `json:"status"`
+	Content     Localized    `json:"content,omitempty"`
+	Platforms   []DeviceType `json:"platforms,omitempty"`
+	Filter      string       `json:"filter_name,omitempty"`
+	Preset      string       `json:"preset,omitempty"`
+}
+
+type HistoryResponse struct {
+	Response
+	Info struct {
+		Rows               []HistoryEntry `json:"rows,omitempty"`
+		LastNotificationId int64          `json:"lastNotificationID,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+// History returns an iterator over the push history. Pages are requested
+// as the iterator advances, so reading stops fetching as soon as the caller
+// stops calling Next. Pages are requested with ctx, or with the client
+// context when ctx is nil.
+func (s MessagesService) History(ctx context.Context, filters HistoryFilters) (*HistoryIterator, error) {
+	if _, err := s.client.authToken(); err != nil {
+		return nil, err
+	}
+	if len(filters.SearchBy) > 0 && len(filters.Value) <= 0 {
+		return nil, errors.New("Search value is required")
+	}
+	return &HistoryIterator{ctx: ctx, service: s, filters: filters}, nil
+}
+
+func (s MessagesService) historyPage(ctx context.Context, filters HistoryFilters, last int64) (*HistoryResponse, error) {
+	body := struct {
+		LastNotificationId int64 `json:"lastNotificationID,omitempty"`
+		HistoryFilters
+	}{last, filters}
+	req, err := s.client.NewAuthRequest("POST", "/getPushHistory", body)
+	if err != nil {
+		return nil, err
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp := new(HistoryResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// HistoryIterator walks the push history, following the pagination cursor
+// of the API:
+//
+//	for it.Next() {
+//		entry := it.Entry()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type HistoryIterator struct {
+	ctx     context.Context
+	service MessagesService
+	filters HistoryFilters
+
+	rows []HistoryEntry
+	last int64
+	done bool
+	err  error
+
+	entry HistoryEntry
+}
+
+// Next advances to the next entry, fetching a new page when needed. It
+// returns false once the history is exhausted or on the first error.
+func (it *HistoryIterator) Next() bool {
+	for len(it.rows) <= 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+	it.entry, it.rows = it.rows[0], it.rows[1:]
+	return true
+}
+
+// Entry returns the entry read by the last call to Next.
+func (it *HistoryIterator) Entry() HistoryEntry {
+	return it.entry
+}
+
+// Err returns the first error found while fetching pages, if any.
+func (it *HistoryIterator) Err() error {
+	return it.err
+}
+
+func (it *HistoryIterator) fetch() {
+	resp, err := it.service.historyPage(it.ctx, it.filters, it.last)
+	if err != nil {
+		it.err = err
+		return
+	}
+	rows := resp.Info.Rows
+	if len(rows) <= 0 {
+		it.done = true
+		return
+	}
+	next := resp.Info.LastNotificationId
+	if next == 0 {
+		next = rows[len(rows)-1].Id
+	}
+	if next == it.last {
+		// The cursor did not move, the page was already returned.
+		it.done = true
+		return
+	}
+	it.last = next
+	it.rows = rows
+}
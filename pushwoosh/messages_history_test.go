@@ -0,0 +1,186 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMessagesService_History(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	pages := map[int64][]HistoryEntry{
+		0: {
+			{Id: 3, Code: "code3", SendDate: "2013-11-14 10:00:00", Status: "done", Content: Text("3"), Platforms: []DeviceType{IOS}},
+			{Id: 2, Code: "code2", SendDate: "2013-11-14 09:00:00", Status: "done", Content: Text("2")},
+		},
+		2: {
+			{Id: 1, Code: "code1", SendDate: "2013-11-14 08:00:00", Status: "done", Content: Localized{"en": "1"}},
+		},
+	}
+
+	requests := 0
+	mux.HandleFunc("/getPushHistory", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Auth               string `json:"auth"`
+				LastNotificationId int64  `json:"lastNotificationID"`
+				Source             string `json:"source"`
+				Limit              int    `json:"limitMessages"`
+			} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		if body.Request.Auth != "testAuthToken" {
+			t.Errorf("Auth = %s, want %s", body.Request.Auth, "testAuthToken")
+		}
+
+		if body.Request.Source != "API" {
+			t.Errorf("Source = %s, want %s", body.Request.Source, "API")
+		}
+
+		if body.Request.Limit != 2 {
+			t.Errorf("Limit = %d, want %d", body.Request.Limit, 2)
+		}
+
+		requests++
+		var res HistoryResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Rows = pages[body.Request.LastNotificationId]
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	it, err := client.Messages.History(context.Background(), HistoryFilters{Source: "API", Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+
+	var entries []HistoryEntry
+	for it.Next() {
+		entries = append(entries, it.Entry())
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := append(pages[0], pages[2]...)
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Entries = %v, want %v", entries, want)
+	}
+	if requests != 3 {
+		t.Errorf("Requests = %d, want %d", requests, 3)
+	}
+}
+
+func TestMessagesService_History_cursorIgnored(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	requests := 0
+	mux.HandleFunc("/getPushHistory", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var res HistoryResponse
+		res.Status = 200
+		res.Info.Rows = []HistoryEntry{{Id: 2, Code: "a"}, {Id: 1, Code: "b"}}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	it, _ := client.Messages.History(context.Background(), HistoryFilters{})
+	var codes []string
+	for it.Next() {
+		codes = append(codes, it.Entry().Code)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("Codes = %v, want %v", codes, want)
+	}
+	if requests != 2 {
+		t.Errorf("Requests = %d, want %d", requests, 2)
+	}
+}
+
+func TestMessagesService_History_error(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/getPushHistory", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Status: 210, Message: "foo"})
+	})
+
+	client.AuthToken = "testAuthToken"
+	it, err := client.Messages.History(context.Background(), HistoryFilters{})
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if it.Next() {
+		t.Errorf("Expected no entries")
+	}
+	want := ErrorResponse{Status: 210, Message: "foo"}
+	if err, ok := it.Err().(ErrorResponse); !ok || err.Status != want.Status {
+		t.Errorf("Error = %v, want %v", it.Err(), want)
+	}
+}
+
+func TestMessagesService_History_canceled(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/getPushHistory", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.AuthToken = "testAuthToken"
+	it, _ := client.Messages.History(ctx, HistoryFilters{})
+	if it.Next() {
+		t.Errorf("Expected no entries")
+	}
+	if it.Err() == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestMessagesService_History_clientContext(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/getPushHistory", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.AuthToken = "testAuthToken"
+	it, _ := client.WithContext(ctx).Messages.History(nil, HistoryFilters{})
+	if it.Next() {
+		t.Errorf("Expected no entries")
+	}
+	if it.Err() == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestMessagesService_History_invalidAuth(t *testing.T) {
+	client := NewClient(nil)
+	_, err := client.Messages.History(context.Background(), HistoryFilters{})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
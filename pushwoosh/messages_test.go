@@ -0,0 +1,331 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMessagesService_Create(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createMessage", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Application   string                   `json:"application"`
+				Auth          string                   `json:"auth"`
+				Notifications []map[string]interface{} `json:"notifications"`
+			} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		if body.Request.Application != "testAppToken" {
+			t.Errorf("Application = %s, want %s", body.Request.Application, "testAppToken")
+		}
+
+		if body.Request.Auth != "testAuthToken" {
+			t.Errorf("Auth = %s, want %s", body.Request.Auth, "testAuthToken")
+		}
+
+		want := []map[string]interface{}{
+			{
+				"send_date": "now",
+				"preset":    "testPreset",
+				"campaign":  "testCampaign",
+				"devices":   []interface{}{"hwid1"},
+				"data":      map[string]interface{}{"name": "Alice"},
+			},
+			{
+				"send_date": "2013-11-14 10:00",
+				"content":   map[string]interface{}{"en": "Hello", "es": "Hola"},
+				"devices":   []interface{}{"hwid2"},
+			},
+		}
+		if !reflect.DeepEqual(body.Request.Notifications, want) {
+			t.Errorf("Notifications = %v, want %v", body.Request.Notifications, want)
+		}
+
+		var res MessagesResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Messages = []string{"code1", "code2"}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Messages.Create(
+		Notification{
+			Preset:   "testPreset",
+			Campaign: "testCampaign",
+			Devices:  []string{"hwid1"},
+			Data:     map[string]string{"name": "Alice"},
+		},
+		Notification{
+			SendDate: "2013-11-14 10:00",
+			Content:  Localized{"en": "Hello", "es": "Hola"},
+			Devices:  []string{"hwid2"},
+		},
+	)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := []string{"code1", "code2"}
+	if !reflect.DeepEqual(resp.Info.Messages, want) {
+		t.Errorf("Messages = %v, want %v", resp.Info.Messages, want)
+	}
+}
+
+func TestMessagesService_Create_invalidApp(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Messages.Create(Notification{Content: Text("Hello")})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestMessagesService_Create_invalidContent(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Messages.Create(Notification{Devices: []string{"hwid1"}})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestLocalized_JSON(t *testing.T) {
+	tests := []struct {
+		text Localized
+		json string
+	}{
+		{Text("Hello"), `"Hello"`},
+		{Localized{"en": "Hello"}, `{"en":"Hello"}`},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(test.text)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+		if string(b) != test.json {
+			t.Errorf("Marshal(%v) = %s, want %s", test.text, b, test.json)
+		}
+		var text Localized
+		if err := json.Unmarshal(b, &text); err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+		if !reflect.DeepEqual(text, test.text) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", b, text, test.text)
+		}
+	}
+}
+
+func TestMessagesService_CreateTargeted(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createTargetedMessage", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"auth":           "testAuthToken",
+			"devices_filter": `A("testAppToken", ["Android"]) * T("cart_value", GTE, 50)`,
+			"send_date":      "now",
+			"content":        "Your cart is waiting",
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+
+		var res TargetedMessageResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.MessageCode = "testMessageCode"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	filter := And(A("testAppToken", "Android"), T("cart_value", GTE, 50))
+	resp, err := client.Messages.CreateTargeted(filter, Notification{
+		Content: Text("Your cart is waiting"),
+	})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.MessageCode != "testMessageCode" {
+		t.Errorf("MessageCode = %s, want %s", resp.Info.MessageCode, "testMessageCode")
+	}
+}
+
+func TestMessagesService_CreateTargeted_invalidFilter(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Messages.CreateTargeted(nil, Notification{Content: Text("Hello")})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestMessagesService_CreateTargeted_invalidDevices(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Messages.CreateTargeted(A("testAppToken"), Notification{
+		Content: Text("Hello"),
+		Devices: []string{"hwid1"},
+	})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestMessagesService_Create_applicationsGroup(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createMessage", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if _, ok := body.Request["application"]; ok {
+			t.Errorf("Application = %v, want none", body.Request["application"])
+		}
+		if body.Request["applications_group"] != "testGroup" {
+			t.Errorf("ApplicationsGroup = %v, want %s", body.Request["applications_group"], "testGroup")
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.AppGroup("testGroup").Messages.Create(Notification{Content: Text("Hello")})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestMessagesService_Create_inbox(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createMessage", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Notifications []map[string]interface{} `json:"notifications"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		want := []map[string]interface{}{{
+			"send_date":     "now",
+			"content":       "Hello",
+			"show_in_inbox": true,
+			"inbox_image":   "https://example.com/inbox.png",
+			"inbox_date":    "2013-12-31",
+		}}
+		if !reflect.DeepEqual(body.Request.Notifications, want) {
+			t.Errorf("Notifications = %v, want %v", body.Request.Notifications, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Messages.Create(Notification{
+		Content:     Text("Hello"),
+		ShowInInbox: true,
+		InboxImage:  "https://example.com/inbox.png",
+		InboxDate:   "2013-12-31",
+	})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestMessagesService_Create_invalidInbox(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Messages.Create(Notification{Content: Text("Hello"), InboxDate: "2013-12-31"})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestMessagesService_Delete(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/deleteMessage", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		want := map[string]interface{}{"auth": "testAuthToken", "message": "testMessage"}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.AuthToken = "testAuthToken"
+	if _, err := client.Messages.Delete("testMessage"); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if _, err := client.Messages.Delete(""); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestMessagesService_Stats(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	rows := []CampaignStat{{Date: "2013-11-14 00:00:00", Action: "open", Count: 3}}
+	mux.HandleFunc("/getMsgStats", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		want := map[string]interface{}{"auth": "testAuthToken", "message": "testMessage"}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+
+		var res MessageStatsResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Rows = rows
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Messages.Stats("testMessage")
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !reflect.DeepEqual(resp.Info.Rows, rows) {
+		t.Errorf("Rows = %v, want %v", resp.Info.Rows, rows)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+// Package metrics collects request metrics from a pushwoosh.Client and
+// serves them in the Prometheus text exposition format, e.g.
+//
+//	collector := metrics.New()
+//	client.Observe(collector)
+//	http.Handle("/metrics", collector)
+//
+// The following metrics are exported:
+//
+//	pushwoosh_requests_total{endpoint,outcome}            counter
+//	pushwoosh_request_duration_seconds{endpoint,outcome}  histogram
+//	pushwoosh_requests_in_flight                          gauge
+//
+// where outcome is one of the pushwoosh.Outcome values. Only requests sent
+// to the API are counted: validation_error is Pushwoosh status 210, while
+// arguments rejected by the client before sending, e.g. by Devices.Register,
+// are not counted at all. Export file downloads are not counted either.
+//
+// The client has no rate limiter, so no wait time metric is exported.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram
+// buckets used by New.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type key struct {
+	endpoint string
+	outcome  pushwoosh.Outcome
+}
+
+type series struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// Collector is a pushwoosh.Observer aggregating request metrics. It is safe
+// for concurrent use and may observe several clients at once.
+type Collector struct {
+	buckets []float64
+
+	mu       sync.Mutex
+	series   map[key]*series
+	inFlight int64
+}
+
+// New returns a Collector using DefaultBuckets.
+func New() *Collector {
+	return NewWithBuckets(DefaultBuckets)
+}
+
+// NewWithBuckets returns a Collector whose latency histograms use the given
+// bucket upper bounds, in seconds.
+func NewWithBuckets(buckets []float64) *Collector {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	return &Collector{
+		buckets: b,
+		series:  map[key]*series{},
+	}
+}
+
+func (c *Collector) RequestStarted(req *http.Request, endpoint string) {
+	c.mu.Lock()
+	c.inFlight++
+	c.mu.Unlock()
+}
+
+func (c *Collector) RequestFinished(req *http.Request, info pushwoosh.RequestInfo) {
+	k := key{info.Endpoint, info.Outcome()}
+	seconds := info.Duration.Seconds()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight--
+	s, ok := c.series[k]
+	if !ok {
+		s = &series{buckets: make([]uint64, len(c.buckets))}
+		c.series[k] = s
+	}
+	s.count++
+	s.sum += seconds
+	for i, le := range c.buckets {
+		if seconds <= le {
+			s.buckets[i]++
+		}
+	}
+}
+
+// InFlight returns the number of requests currently in flight.
+func (c *Collector) InFlight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.inFlight
+}
+
+// Count returns the number of requests finished for an endpoint and outcome.
+func (c *Collector) Count(endpoint string, outcome pushwoosh.Outcome) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.series[key{endpoint, outcome}]; ok {
+		return s.count
+	}
+	return 0
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	keys := make([]key, 0, len(c.series))
+	snapshot := make(map[key]series, len(c.series))
+	for k, s := range c.series {
+		keys = append(keys, k)
+		snapshot[k] = series{s.count, s.sum, append([]uint64(nil), s.buckets...)}
+	}
+	inFlight := c.inFlight
+	c.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].outcome < keys[j].outcome
+	})
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	fmt.Fprintln(cw, "# HELP pushwoosh_requests_total Requests made to the Pushwoosh API.")
+	fmt.Fprintln(cw, "# TYPE pushwoosh_requests_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(cw, "pushwoosh_requests_total{%s} %d\n", labels(k), snapshot[k].count)
+	}
+
+	fmt.Fprintln(cw, "# HELP pushwoosh_request_duration_seconds Latency of requests made to the Pushwoosh API.")
+	fmt.Fprintln(cw, "# TYPE pushwoosh_request_duration_seconds histogram")
+	for _, k := range keys {
+		s, l := snapshot[k], labels(k)
+		for i, le := range c.buckets {
+			fmt.Fprintf(cw, "pushwoosh_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", l, formatFloat(le), s.buckets[i])
+		}
+		fmt.Fprintf(cw, "pushwoosh_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", l, s.count)
+		fmt.Fprintf(cw, "pushwoosh_request_duration_seconds_sum{%s} %s\n", l, formatFloat(s.sum))
+		fmt.Fprintf(cw, "pushwoosh_request_duration_seconds_count{%s} %d\n", l, s.count)
+	}
+
+	fmt.Fprintln(cw, "# HELP pushwoosh_requests_in_flight Requests to the Pushwoosh API awaiting a response.")
+	fmt.Fprintln(cw, "# TYPE pushwoosh_requests_in_flight gauge")
+	fmt.Fprintf(cw, "pushwoosh_requests_in_flight %d\n", inFlight)
+
+	if err := cw.w.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+func labels(k key) string {
+	return fmt.Sprintf("endpoint=\"%s\",outcome=\"%s\"", escape(k.endpoint), escape(string(k.outcome)))
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countWriter counts the bytes written through it and keeps the first error.
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
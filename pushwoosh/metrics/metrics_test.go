@@ -0,0 +1,111 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+)
+
+func TestCollector(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	collector := New()
+	mux.HandleFunc("/setBadge", func(w http.ResponseWriter, r *http.Request) {
+		if collector.InFlight() != 1 {
+			t.Errorf("InFlight = %d, want %d", collector.InFlight(), 1)
+		}
+		json.NewEncoder(w).Encode(pushwoosh.Response{Status: 200, Message: "OK"})
+	})
+	mux.HandleFunc("/setTags", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pushwoosh.Response{Status: 210, Message: "foo"})
+	})
+
+	client := pushwoosh.NewClient(nil)
+	u, _ := url.Parse(server.URL)
+	client.CacheAddrInfo = false
+	client.SetBaseURL(u)
+	client.Application = "testAppToken"
+	client.Observe(collector)
+
+	device := pushwoosh.Device{HardwareId: "testHardwareId"}
+	client.Devices.SetBadge(device, 1)
+	client.Devices.SetBadge(device, 2)
+	client.Devices.SetTags(device)
+
+	if n := collector.Count("/setBadge", pushwoosh.OutcomeOK); n != 2 {
+		t.Errorf("Count = %d, want %d", n, 2)
+	}
+	if n := collector.Count("/setTags", pushwoosh.OutcomeValidationError); n != 1 {
+		t.Errorf("Count = %d, want %d", n, 1)
+	}
+	if collector.InFlight() != 0 {
+		t.Errorf("InFlight = %d, want %d", collector.InFlight(), 0)
+	}
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	collector := NewWithBuckets([]float64{1, 0.1})
+	collector.RequestStarted(nil, "/pushStat")
+	collector.RequestStarted(nil, "/pushStat")
+	collector.RequestFinished(nil, pushwoosh.RequestInfo{
+		Endpoint:   "/pushStat",
+		Duration:   50 * time.Millisecond,
+		HTTPStatus: 200,
+		APIStatus:  200,
+	})
+	collector.RequestStarted(nil, "/pushStat")
+	collector.RequestFinished(nil, pushwoosh.RequestInfo{
+		Endpoint: "/pushStat",
+		Duration: 2 * time.Second,
+		Err:      errors.New("foo"),
+	})
+
+	w := httptest.NewRecorder()
+	collector.ServeHTTP(w, nil)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %s, want the Prometheus text format", ct)
+	}
+
+	body := w.Body.String()
+	want := []string{
+		"# TYPE pushwoosh_requests_total counter\n",
+		`pushwoosh_requests_total{endpoint="/pushStat",outcome="ok"} 1` + "\n",
+		`pushwoosh_requests_total{endpoint="/pushStat",outcome="transport_error"} 1` + "\n",
+		"# TYPE pushwoosh_request_duration_seconds histogram\n",
+		`pushwoosh_request_duration_seconds_bucket{endpoint="/pushStat",outcome="ok",le="0.1"} 1` + "\n",
+		`pushwoosh_request_duration_seconds_bucket{endpoint="/pushStat",outcome="ok",le="1"} 1` + "\n",
+		`pushwoosh_request_duration_seconds_bucket{endpoint="/pushStat",outcome="transport_error",le="1"} 0` + "\n",
+		`pushwoosh_request_duration_seconds_bucket{endpoint="/pushStat",outcome="transport_error",le="+Inf"} 1` + "\n",
+		`pushwoosh_request_duration_seconds_sum{endpoint="/pushStat",outcome="transport_error"} 2` + "\n",
+		`pushwoosh_request_duration_seconds_count{endpoint="/pushStat",outcome="ok"} 1` + "\n",
+		"pushwoosh_requests_in_flight 1\n",
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line) {
+			t.Errorf("Body = %s, want it to contain %q", body, line)
+		}
+	}
+	if strings.Index(body, `outcome="ok"} 1`) > strings.Index(body, `outcome="transport_error"} 1`) {
+		t.Errorf("Body = %s, want series sorted by outcome", body)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escape = %s, want %s", got, `a\"b\\c\nd`)
+	}
+}
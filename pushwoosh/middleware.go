@@ -0,0 +1,65 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"net/http"
+)
+
+// RoundTripFunc adapts a function to http.RoundTripper.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the sending of every request made by a client, e.g.
+//
+//	client.Use(func(next http.RoundTripper) http.RoundTripper {
+//		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+//			start := time.Now()
+//			resp, err := next.RoundTrip(req)
+//			log.Println(req.URL.Path, time.Since(start))
+//			return resp, err
+//		})
+//	})
+//
+// Middlewares reading the request body must restore it, req.GetBody is
+// always set for requests built by the client.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Use appends middlewares to the client chain. The first middleware added
+// is the outermost one, seeing requests first and responses last.
+func (c *Client) Use(middlewares ...Middleware) {
+	// Clip the chain so views built with App never share appends.
+	chain := c.middlewares[:len(c.middlewares):len(c.middlewares)]
+	c.middlewares = append(chain, middlewares...)
+}
+
+// HeaderMiddleware sets the given headers on every request.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			for key, values := range header {
+				req.Header[key] = values
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// send performs the request through the middleware chain, or answers it in
+// place of the API in dry-run mode.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	var rt http.RoundTripper = RoundTripFunc(c.client.Do)
+	if c.DryRun {
+		rt = RoundTripFunc(c.dryRun)
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		rt = c.middlewares[i](rt)
+	}
+	return rt.RoundTrip(req)
+}
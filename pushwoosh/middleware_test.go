@@ -0,0 +1,83 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_Use(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/setBadge", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "foo" {
+			t.Errorf("X-Test = %s, want %s", r.Header.Get("X-Test"), "foo")
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+req.URL.Path)
+				resp, err := next.RoundTrip(req)
+				calls = append(calls, name+" done")
+				return resp, err
+			})
+		}
+	}
+	client.Use(trace("outer"), HeaderMiddleware(http.Header{"X-Test": {"foo"}}))
+	client.Use(trace("inner"))
+
+	client.Application = "testAppToken"
+	if _, err := client.Devices.SetBadge(Device{HardwareId: "testHardwareId"}, 1); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := []string{"outer /setBadge", "inner /setBadge", "inner done", "outer done"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Calls = %v, want %v", calls, want)
+	}
+}
+
+func TestClient_Use_faultInjection(t *testing.T) {
+	_, server, client := sandbox()
+	defer server.Close()
+
+	fault := errors.New("injected")
+	client.Use(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, fault
+		})
+	})
+
+	client.Application = "testAppToken"
+	_, err := client.Devices.SetBadge(Device{HardwareId: "testHardwareId"}, 1)
+	if err != fault {
+		t.Errorf("Error = %v, want %v", err, fault)
+	}
+}
+
+func TestClient_Use_views(t *testing.T) {
+	client := NewClient(nil)
+	noop := func(next http.RoundTripper) http.RoundTripper { return next }
+	client.Use(noop, noop)
+
+	a := client.App("a")
+	b := client.App("b")
+	a.Use(noop)
+	b.Use(noop, noop)
+
+	if len(client.middlewares) != 2 || len(a.middlewares) != 3 || len(b.middlewares) != 4 {
+		t.Errorf("Middlewares = %d, %d, %d, want 2, 3, 4",
+			len(client.middlewares), len(a.middlewares), len(b.middlewares))
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Observer is notified of every request made through Client.Do, e.g. to
+// export metrics. RequestStarted and RequestFinished are called in pairs, with
+// the same req, and may be called concurrently for different requests.
+// RequestStarted may add headers to req before it is sent.
+//
+// Only requests sent are observed: arguments rejected by the client, e.g. a
+// device without push token, fail before any request is made, and export
+// file downloads by ExportsService.Download are not API calls.
+type Observer interface {
+	RequestStarted(req *http.Request, endpoint string)
+	RequestFinished(req *http.Request, info RequestInfo)
+}
+
+// Outcome classifies how a request ended.
+type Outcome string
+
+const (
+	OutcomeOK Outcome = "ok"
+	// OutcomeHTTPError is a response with an HTTP status other than 200.
+	OutcomeHTTPError Outcome = "http_error"
+	// OutcomeAPIError is a response with a Pushwoosh status other than 200,
+	// or one that could not be decoded.
+	OutcomeAPIError Outcome = "api_error"
+	// OutcomeValidationError is a request whose arguments were rejected by
+	// the API, Pushwoosh status 210. Arguments rejected by the client are
+	// never sent, so they are not observed.
+	OutcomeValidationError Outcome = "validation_error"
+	// OutcomeTransportError is a request that got no response at all.
+	OutcomeTransportError Outcome = "transport_error"
+)
+
+// RequestInfo describes a finished request.
+type RequestInfo struct {
+	// Endpoint is the request path relative to the base URL, e.g.
+	// "/registerDevice".
+	Endpoint string
+	Duration time.Duration
+	// HTTPStatus is zero when no response was received.
+	HTTPStatus int
+	// APIStatus is zero when no Pushwoosh status was decoded.
+	APIStatus int
+	// Result is the value the response was decoded into, e.g. a
+	// *TagsResponse for "/setTags".
+	Result interface{}
+	Err    error
+}
+
+// Outcome classifies the request.
+func (i RequestInfo) Outcome() Outcome {
+	switch {
+	case i.Err == nil:
+		return OutcomeOK
+	case i.HTTPStatus == 0:
+		return OutcomeTransportError
+	case i.HTTPStatus != 200:
+		return OutcomeHTTPError
+	case i.APIStatus == 210:
+		return OutcomeValidationError
+	default:
+		return OutcomeAPIError
+	}
+}
+
+// Observe appends observers to the client. Like middlewares, observers
+// added to a client are inherited by the views built from it afterwards.
+func (c *Client) Observe(observers ...Observer) {
+	list := c.observers[:len(c.observers):len(c.observers)]
+	c.observers = append(list, observers...)
+}
+
+// endpointName returns the request path relative to the client base URL.
+func endpointName(c *Client, req *http.Request) string {
+	p := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(c.BaseURL().Path, "/"))
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type observerTest struct {
+	started  []string
+	finished []RequestInfo
+}
+
+func (o *observerTest) RequestStarted(req *http.Request, endpoint string) {
+	o.started = append(o.started, endpoint)
+}
+
+func (o *observerTest) RequestFinished(req *http.Request, info RequestInfo) {
+	o.finished = append(o.finished, info)
+}
+
+func TestClient_Observe(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/setBadge", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+	mux.HandleFunc("/setTags", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Status: 210, Message: "foo"})
+	})
+	mux.HandleFunc("/pushStat", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "foo", http.StatusBadGateway)
+	})
+
+	observer := new(observerTest)
+	client.Observe(observer)
+	client.Application = "testAppToken"
+	device := Device{HardwareId: "testHardwareId"}
+	client.Devices.SetBadge(device, 1)
+	client.Devices.SetTags(device)
+	client.Devices.PushStat(device, "testHash")
+
+	if len(observer.started) != 3 || len(observer.finished) != 3 {
+		t.Fatalf("Calls = %d, %d, want 3, 3", len(observer.started), len(observer.finished))
+	}
+	if observer.started[1] != "/setTags" {
+		t.Errorf("Started = %s, want %s", observer.started[1], "/setTags")
+	}
+	if _, ok := observer.finished[1].Result.(*TagsResponse); !ok {
+		t.Errorf("Result = %T, want *TagsResponse", observer.finished[1].Result)
+	}
+	tests := []struct {
+		endpoint   string
+		httpStatus int
+		apiStatus  int
+		outcome    Outcome
+	}{
+		{"/setBadge", 200, 200, OutcomeOK},
+		{"/setTags", 200, 210, OutcomeValidationError},
+		{"/pushStat", 502, 0, OutcomeHTTPError},
+	}
+	for i, test := range tests {
+		info := observer.finished[i]
+		if info.Endpoint != test.endpoint {
+			t.Errorf("Endpoint = %s, want %s", info.Endpoint, test.endpoint)
+		}
+		if info.HTTPStatus != test.httpStatus || info.APIStatus != test.apiStatus {
+			t.Errorf("Statuses = %d, %d, want %d, %d", info.HTTPStatus, info.APIStatus, test.httpStatus, test.apiStatus)
+		}
+		if info.Outcome() != test.outcome {
+			t.Errorf("Outcome = %s, want %s", info.Outcome(), test.outcome)
+		}
+	}
+}
+
+func TestRequestInfo_Outcome(t *testing.T) {
+	fault := errors.New("foo")
+	tests := []struct {
+		info RequestInfo
+		want Outcome
+	}{
+		{RequestInfo{HTTPStatus: 200, APIStatus: 200}, OutcomeOK},
+		{RequestInfo{Err: fault}, OutcomeTransportError},
+		{RequestInfo{HTTPStatus: 500, Err: fault}, OutcomeHTTPError},
+		{RequestInfo{HTTPStatus: 200, APIStatus: 210, Err: fault}, OutcomeValidationError},
+		{RequestInfo{HTTPStatus: 200, APIStatus: 400, Err: fault}, OutcomeAPIError},
+		{RequestInfo{HTTPStatus: 200, Err: fault}, OutcomeAPIError},
+	}
+	for _, test := range tests {
+		if got := test.info.Outcome(); got != test.want {
+			t.Errorf("Outcome(%+v) = %s, want %s", test.info, got, test.want)
+		}
+	}
+}
+
+func TestEndpointName(t *testing.T) {
+	client := NewClient(nil)
+	req, _ := client.NewRequest("POST", "/exportSegment/result", nil)
+	if name := endpointName(client, req); name != "/exportSegment/result" {
+		t.Errorf("endpointName = %s, want %s", name, "/exportSegment/result")
+	}
+
+	u, _ := url.Parse("http://127.0.0.1")
+	client.SetBaseURL(u)
+	req, _ = client.NewRequest("POST", "/setTags", nil)
+	if name := endpointName(client, req); name != "/setTags" {
+		t.Errorf("endpointName = %s, want %s", name, "/setTags")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+// Preset is a notification template, referenced by its code from
+// Notification.Preset.
+type Preset struct {
+	Code      string       `json:"code,omitempty"`
+	Name      string       `json:"name"`
+	Content   Localized    `json:"content,omitempty"`
+	Data      interface{}  `json:"data,omitempty"`
+	Platforms []DeviceType `json:"platforms,omitempty"`
+	Link      string       `json:"link,omitempty"`
+	PageId    int          `json:"page_id,omitempty"`
+}
+
+type PresetResponse struct {
+	Response
+	Info Preset `json:"response,omitempty"`
+}
+
+type PresetsResponse struct {
+	Response
+	Info struct {
+		Presets []Preset `json:"presets,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type PresetsService struct {
+	client *Client
+}
+
+func (s PresetsService) List() (*PresetsResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+	}{s.client.Application}
+	req, err := s.client.NewAuthRequest("POST", "/listPresets", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(PresetsResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s PresetsService) Get(code string) (*PresetResponse, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Preset code is required")
+	}
+	body := struct {
+		Code string `json:"preset_code"`
+	}{code}
+	req, err := s.client.NewAuthRequest("POST", "/getPreset", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(PresetResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// Create stores the preset. The code of the new preset is returned in the
+// response.
+func (s PresetsService) Create(preset Preset) (*PresetResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(preset.Name) <= 0 {
+		return nil, errors.New("Preset name is required")
+	}
+	if len(preset.Content) <= 0 {
+		return nil, errors.New("Preset content is required")
+	}
+	preset.Code = ""
+	body := struct {
+		Application string `json:"application"`
+		Preset
+	}{s.client.Application, preset}
+	req, err := s.client.NewAuthRequest("POST", "/createPreset", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(PresetResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s PresetsService) Delete(code string) (*Response, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Preset code is required")
+	}
+	body := struct {
+		Code string `json:"preset_code"`
+	}{code}
+	req, err := s.client.NewAuthRequest("POST", "/deletePreset", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
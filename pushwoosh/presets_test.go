@@ -0,0 +1,159 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPresetsService_Create(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createPreset", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Auth        string       `json:"auth"`
+				Application string       `json:"application"`
+				Code        string       `json:"code"`
+				Name        string       `json:"name"`
+				Content     Localized    `json:"content"`
+				Platforms   []DeviceType `json:"platforms"`
+			} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		if body.Request.Auth != "testAuthToken" {
+			t.Errorf("Auth = %s, want %s", body.Request.Auth, "testAuthToken")
+		}
+
+		if body.Request.Application != "testAppToken" {
+			t.Errorf("Application = %s, want %s", body.Request.Application, "testAppToken")
+		}
+
+		if len(body.Request.Code) > 0 {
+			t.Errorf("Code = %s, want none", body.Request.Code)
+		}
+
+		if body.Request.Name != "welcome" {
+			t.Errorf("Name = %s, want %s", body.Request.Name, "welcome")
+		}
+
+		content := Localized{"en": "Welcome!"}
+		if !reflect.DeepEqual(body.Request.Content, content) {
+			t.Errorf("Content = %v, want %v", body.Request.Content, content)
+		}
+
+		platforms := []DeviceType{IOS, Android}
+		if !reflect.DeepEqual(body.Request.Platforms, platforms) {
+			t.Errorf("Platforms = %v, want %v", body.Request.Platforms, platforms)
+		}
+
+		var res PresetResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Code = "testPreset"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Presets.Create(Preset{
+		Code:      "ignored",
+		Name:      "welcome",
+		Content:   Localized{"en": "Welcome!"},
+		Platforms: []DeviceType{IOS, Android},
+	})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.Code != "testPreset" {
+		t.Errorf("Code = %s, want %s", resp.Info.Code, "testPreset")
+	}
+}
+
+func TestPresetsService_Create_invalidName(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Presets.Create(Preset{Content: Text("Welcome!")})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestPresetsService_List(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/listPresets", func(w http.ResponseWriter, r *http.Request) {
+		var res PresetsResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Presets = []Preset{{Code: "testPreset", Name: "welcome"}}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Presets.List()
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := []Preset{{Code: "testPreset", Name: "welcome"}}
+	if !reflect.DeepEqual(resp.Info.Presets, want) {
+		t.Errorf("Presets = %v, want %v", resp.Info.Presets, want)
+	}
+}
+
+func TestPresetsService_Get(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/getPreset", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Code string `json:"preset_code"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Request.Code != "testPreset" {
+			t.Errorf("Code = %s, want %s", body.Request.Code, "testPreset")
+		}
+
+		var res PresetResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info = Preset{Code: "testPreset", Name: "welcome", Content: Text("Welcome!")}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Presets.Get("testPreset")
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	want := Preset{Code: "testPreset", Name: "welcome", Content: Text("Welcome!")}
+	if !reflect.DeepEqual(resp.Info, want) {
+		t.Errorf("Preset = %v, want %v", resp.Info, want)
+	}
+}
+
+func TestPresetsService_Delete_invalidCode(t *testing.T) {
+	client := NewClient(nil)
+	client.AuthToken = "testAuthToken"
+	_, err := client.Presets.Delete("")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
@@ -7,14 +7,18 @@ package pushwoosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math/rand"
 	"net"
 	"net/http"
 	"net/url"
 	"path"
+	"time"
 )
 
 const (
@@ -22,21 +26,59 @@ const (
 	PushwooshVersion = "1.3"
 )
 
+// DefaultMaxResponseSize is the largest response body read when
+// Client.MaxResponseSize is not set.
+const DefaultMaxResponseSize = 10 << 20
+
+// maxDrainSize is the most left of a response body read before closing it,
+// so its connection is reused.
+const maxDrainSize = 64 << 10
+
 type Client struct {
 	Application string
-	AuthToken   string
+	// ApplicationsGroup targets every application of a group. It is only
+	// honored by MessagesService.Create, and only when Application is empty.
+	ApplicationsGroup string
+	// AuthToken is the API access token, used when Credentials is nil.
+	AuthToken string
+	// Credentials supplies the API access token for every request that
+	// requires one, taking precedence over AuthToken.
+	Credentials CredentialsProvider
 	// getaddrinfo may not be thread-safe on some systems. Enabling AddrInfo
 	// cache ensures parallel bulk operations to work propertly without
 	// getaddrinfo errors.
 	// More info at https://code.google.com/p/go/issues/detail?id=3575
 	CacheAddrInfo bool
 	UserAgent     string
+	// Logger, when set, records every API request made by the client.
+	// Export file downloads are not logged.
+	Logger Logger
+	// MaxResponseSize is the largest response body read, in bytes.
+	// DefaultMaxResponseSize is used when zero.
+	MaxResponseSize int64
+	// DryRun makes the client validate and build every request, but answer
+	// it with an empty successful response instead of sending it. The
+	// requests built are passed to DryRunSink, when set.
+	DryRun     bool
+	DryRunSink func(DryRunRequest)
 
-	Devices *DevicesService
+	Applications *ApplicationsService
+	Campaigns    *CampaignsService
+	Devices      *DevicesService
+	Emails       *EmailsService
+	Exports      *ExportsService
+	Filters      *FiltersService
+	Messages     *MessagesService
+	Presets      *PresetsService
+	RichMedia    *RichMediaService
+	TestDevices  *TestDevicesService
 
-	baseURL   *url.URL
-	client    *http.Client
-	endpoints []*url.URL
+	baseURL     *url.URL
+	client      *http.Client
+	endpoints   []*url.URL
+	ctx         context.Context
+	middlewares []Middleware
+	observers   []Observer
 }
 
 func (c *Client) BaseURL() *url.URL {
@@ -53,49 +95,175 @@ func NewClient(client *http.Client) *Client {
 	baseURL, _ := url.Parse(defaultBaseURL())
 	c.SetBaseURL(baseURL)
 	c.UserAgent = defaultUserAgent()
-	c.Devices = &DevicesService{&c}
 	c.CacheAddrInfo = true
+	c.setServices()
 	return &c
 }
 
+// App returns a view of the client scoped to another application. The view
+// shares the HTTP client of c and copies the rest of its settings, so it is
+// cheap enough to be built per request:
+//
+//	client.App("XXXXX-XXXXX").Devices.Register(device)
+func (c *Client) App(code string) *Client {
+	v := *c
+	v.Application = code
+	v.ApplicationsGroup = ""
+	v.setServices()
+	return &v
+}
+
+// AppGroup returns a view of the client, like App, whose messages target
+// every application of the given group.
+func (c *Client) AppGroup(code string) *Client {
+	v := *c
+	v.Application = ""
+	v.ApplicationsGroup = code
+	v.setServices()
+	return &v
+}
+
+// WithContext returns a view of the client, like App, whose requests carry
+// ctx, so they are canceled with it and traced under it:
+//
+//	client.WithContext(ctx).Devices.SetTags(device)
+func (c *Client) WithContext(ctx context.Context) *Client {
+	v := *c
+	v.ctx = ctx
+	v.setServices()
+	return &v
+}
+
+// requestContext returns ctx, or the client context when ctx is nil.
+func (c *Client) requestContext(ctx context.Context) context.Context {
+	if ctx != nil {
+		return ctx
+	}
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+func (c *Client) setServices() {
+	c.Applications = &ApplicationsService{c}
+	c.Campaigns = &CampaignsService{c}
+	c.Devices = &DevicesService{c}
+	c.Emails = &EmailsService{c}
+	c.Exports = &ExportsService{c}
+	c.Filters = &FiltersService{c}
+	c.Messages = &MessagesService{c}
+	c.Presets = &PresetsService{c}
+	c.RichMedia = &RichMediaService{c}
+	c.TestDevices = &TestDevicesService{c}
+}
+
 func (c *Client) Do(req *http.Request, r interface{}) error {
-	resp, err := c.client.Do(req)
-	if err != nil {
+	if c.Logger == nil && len(c.observers) == 0 {
+		_, err := c.do(req, r)
 		return err
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(r)
-	defer resp.Body.Close()
+	endpoint := endpointName(c, req)
+	for _, o := range c.observers {
+		o.RequestStarted(req, endpoint)
+	}
+	start := time.Now()
+	resp, err := c.do(req, r)
+	info := RequestInfo{
+		Endpoint: endpoint,
+		Duration: time.Since(start),
+		Result:   r,
+		Err:      err,
+	}
+	if resp != nil {
+		info.HTTPStatus = resp.StatusCode
+	}
+	if rp, ok := apiResponse(r); ok && resp != nil && resp.StatusCode == 200 {
+		info.APIStatus = rp.Status
+	}
+
+	if c.Logger != nil {
+		c.logRequest(req, info)
+	}
+	for _, o := range c.observers {
+		o.RequestFinished(req, info)
+	}
+	return err
+}
+
+func (c *Client) do(req *http.Request, r interface{}) (*http.Response, error) {
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(resp.Body)
+
+	err = c.decode(resp, r)
 
 	if resp.StatusCode != 200 {
-		if rp, ok := r.(*Response); ok && err != nil {
+		if rp, ok := apiResponse(r); ok && err != nil {
 			rp.Message = resp.Status
 			rp.Response = resp
 			rp.Status = resp.StatusCode
 		}
-		return errors.New(resp.Status)
+		if decodeErr, ok := err.(*DecodeError); ok {
+			return resp, decodeErr
+		}
+		return resp, errors.New(resp.Status)
 	}
 
-	rp, ok := r.(*Response)
+	rp, ok := apiResponse(r)
 	if err != nil {
 		if resp.StatusCode != 200 && ok {
 			rp.Message = resp.Status
 			rp.Response = resp
 			rp.Status = resp.StatusCode
-			return errors.New(rp.Message)
+			return resp, errors.New(rp.Message)
 		}
-		return err
+		return resp, err
 	}
 	if ok {
 		rp.Response = resp
 
 		if rp.Status != 200 {
-			return ErrorResponse(*rp)
+			return resp, ErrorResponse(*rp)
 		}
 	}
+	return resp, nil
+}
+
+// decode reads a whole response body into r. Bodies larger than
+// MaxResponseSize, or not holding a single JSON value, fail with a
+// DecodeError.
+func (c *Client) decode(resp *http.Response, r interface{}) error {
+	limit := c.MaxResponseSize
+	if limit <= 0 {
+		limit = DefaultMaxResponseSize
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(body)) > limit {
+		body, err = body[:limit], ErrResponseTooLarge
+	} else {
+		err = json.Unmarshal(body, r)
+	}
+	if err != nil {
+		return &DecodeError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body, Err: err}
+	}
 	return nil
 }
 
+// closeBody drains the rest of a response body, up to maxDrainSize, and
+// closes it.
+func closeBody(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, maxDrainSize))
+	body.Close()
+}
+
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
 	rel, err := url.Parse(path.Join(c.BaseURL().Path + urlStr))
 	if err != nil {
@@ -116,12 +284,51 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if err != nil {
 		return nil, err
 	}
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
 
 	req.Header.Set("User-Agent", c.UserAgent)
 	c.CacheAddrInfo = true
 	return req, nil
 }
 
+// NewAuthRequest works as NewRequest, adding the API access token to the
+// body as the "auth" field. The body must encode as a JSON object.
+func (c *Client) NewAuthRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+	token, err := c.authToken()
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return nil, err
+		}
+	}
+	fields["auth"], _ = json.Marshal(token)
+	return c.NewRequest(method, urlStr, fields)
+}
+
+func (c *Client) authToken() (string, error) {
+	token := c.AuthToken
+	if c.Credentials != nil {
+		var err error
+		token, err = c.Credentials.AuthToken()
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(token) <= 0 {
+		return "", errors.New("Auth token is required")
+	}
+	return token, nil
+}
+
 func (c *Client) SetBaseURL(url *url.URL) {
 	c.baseURL = url
 	c.endpoints = nil
@@ -147,12 +354,53 @@ type Response struct {
 	Status   int            `json:"status_code"`
 }
 
+func (r *Response) apiResponse() *Response {
+	return r
+}
+
+// apiResponse returns the Response embedded in r, if any, so response types
+// extending Response get their status checked as well.
+func apiResponse(r interface{}) (*Response, bool) {
+	if ar, ok := r.(interface {
+		apiResponse() *Response
+	}); ok {
+		return ar.apiResponse(), true
+	}
+	return nil, false
+}
+
 type ErrorResponse Response
 
 func (e ErrorResponse) Error() string {
 	return fmt.Sprintf("(Code: %d) %s", e.Status, e.Message)
 }
 
+// ErrResponseTooLarge is the DecodeError cause of response bodies larger
+// than Client.MaxResponseSize.
+var ErrResponseTooLarge = errors.New("Response body too large")
+
+// DecodeError is a response body that could not be decoded, e.g. the HTML
+// page of a failing proxy. Body holds the raw bytes read, up to
+// Client.MaxResponseSize.
+type DecodeError struct {
+	// StatusCode and Status are the HTTP status of the response.
+	StatusCode int
+	Status     string
+	Body       []byte
+	Err        error
+}
+
+func (e *DecodeError) Error() string {
+	if e.StatusCode != 200 {
+		return fmt.Sprintf("%s: Invalid response body: %s", e.Status, e.Err)
+	}
+	return "Invalid response body: " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
 const (
 	defaultBaseURLPattern   = "https://cp.pushwoosh.com/json/%s/"
 	defaultUserAgentPattern = "go-pushwoosh/%s"
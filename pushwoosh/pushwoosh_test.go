@@ -7,12 +7,16 @@ package pushwoosh
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"sync/atomic"
 	"testing"
 )
 
@@ -26,6 +30,68 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestClient_App(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/setBadge", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Application string `json:"application"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Request.Application != "otherAppToken" {
+			t.Errorf("Application = %s, want %s", body.Request.Application, "otherAppToken")
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	app := client.App("otherAppToken")
+	if _, err := app.Devices.SetBadge(Device{HardwareId: "testHardwareId"}, 1); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+
+	if client.Application != "testAppToken" {
+		t.Errorf("Application = %s, want %s", client.Application, "testAppToken")
+	}
+	if app.AuthToken != client.AuthToken {
+		t.Errorf("AuthToken = %s, want %s", app.AuthToken, client.AuthToken)
+	}
+	if app.client != client.client {
+		t.Errorf("Expected the HTTP client to be shared")
+	}
+	if app.Devices.client != app || client.Devices.client != client {
+		t.Errorf("Expected every view services to use its own client")
+	}
+}
+
+func TestClient_WithContext(t *testing.T) {
+	_, server, client := sandbox()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	view := client.WithContext(ctx)
+	req, err := view.NewRequest("POST", "/setBadge", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if req.Context() != ctx {
+		t.Errorf("Expected the request to carry the view context")
+	}
+
+	view.Application = "testAppToken"
+	if _, err := view.Devices.SetBadge(Device{HardwareId: "testHardwareId"}, 1); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if client.ctx != nil {
+		t.Errorf("Expected the client context to be left unset")
+	}
+}
+
 func TestNewRequest(t *testing.T) {
 	c := NewClient(nil)
 
@@ -52,6 +118,49 @@ func TestNewRequest(t *testing.T) {
 	}
 }
 
+func TestNewAuthRequest(t *testing.T) {
+	c := NewClient(nil)
+	c.AuthToken = "ignored"
+	c.Credentials = StaticCredentials("testAuthToken")
+
+	inBody := struct {
+		TestField string `json:"testfield"`
+	}{"test value"}
+	req, err := c.NewAuthRequest("POST", "/foo", inBody)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+
+	var body struct {
+		Request map[string]string `json:"request"`
+	}
+	json.NewDecoder(req.Body).Decode(&body)
+	want := map[string]string{"auth": "testAuthToken", "testfield": "test value"}
+	if !reflect.DeepEqual(body.Request, want) {
+		t.Errorf("Body = %v, want %v", body.Request, want)
+	}
+}
+
+func TestNewAuthRequest_invalidAuth(t *testing.T) {
+	c := NewClient(nil)
+	if _, err := c.NewAuthRequest("POST", "/foo", nil); err == nil {
+		t.Errorf("Expected an error")
+	}
+
+	c.Credentials = EnvCredentials("PUSHWOOSH_TEST_UNSET")
+	if _, err := c.NewAuthRequest("POST", "/foo", nil); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestNewAuthRequest_invalidBody(t *testing.T) {
+	c := NewClient(nil)
+	c.AuthToken = "testAuthToken"
+	if _, err := c.NewAuthRequest("POST", "/foo", []string{"foo"}); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
 func TestNewRequest_invalidJSON(t *testing.T) {
 	c := NewClient(nil)
 	_, err := c.NewRequest("GET", "/", &struct{ InvalidField map[int]int }{})
@@ -161,6 +270,109 @@ func TestDo_invalidJSON(t *testing.T) {
 	}
 }
 
+func TestDo_trailingData(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	body := `{"status_code":200,"status_message":"OK"} trailing`
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+
+	req, _ := client.NewRequest("POST", "/", nil)
+	var resp Response
+	err := client.Do(req, &resp)
+
+	decodeErr, ok := err.(*DecodeError)
+	if !ok {
+		t.Fatalf("Expected a decode error, found %v", err)
+	}
+	if string(decodeErr.Body) != body {
+		t.Errorf("Body = %s, want %s", decodeErr.Body, body)
+	}
+}
+
+func TestDo_maxResponseSize(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response{Message: "OK", Status: 200})
+	})
+
+	client.MaxResponseSize = 10
+	req, _ := client.NewRequest("POST", "/", nil)
+	var resp Response
+	err := client.Do(req, &resp)
+
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected a response too large error, found %v", err)
+	}
+	decodeErr := err.(*DecodeError)
+	if want := `{"status_m`; string(decodeErr.Body) != want {
+		t.Errorf("Body = %s, want %s", decodeErr.Body, want)
+	}
+}
+
+func TestDo_httpDecodeError(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	body := "<html><body>Bad Gateway</body></html>"
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(body))
+	})
+
+	req, _ := client.NewRequest("POST", "/", nil)
+	var resp Response
+	err := client.Do(req, &resp)
+
+	decodeErr, ok := err.(*DecodeError)
+	if !ok {
+		t.Fatalf("Expected a decode error, found %v", err)
+	}
+	if decodeErr.StatusCode != http.StatusBadGateway || decodeErr.Status != "502 Bad Gateway" {
+		t.Errorf("Status = %d %s, want %d", decodeErr.StatusCode, decodeErr.Status, http.StatusBadGateway)
+	}
+	if string(decodeErr.Body) != body {
+		t.Errorf("Body = %s, want %s", decodeErr.Body, body)
+	}
+	if resp.Status != http.StatusBadGateway {
+		t.Errorf("Response status = %d, want %d", resp.Status, http.StatusBadGateway)
+	}
+}
+
+func TestDo_reusesConnections(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewUnstartedServer(mux)
+	var conns int32
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+	client := NewClient(nil)
+	u, _ := url.Parse(server.URL)
+	client.SetBaseURL(u)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json "))
+		w.Write(bytes.Repeat([]byte("x"), 8<<10))
+	})
+
+	for i := 0; i < 3; i++ {
+		req, _ := client.NewRequest("POST", "/", nil)
+		if err := client.Do(req, &Response{}); err == nil {
+			t.Errorf("Expected an error")
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("Connections = %d, want %d", n, 1)
+	}
+}
+
 func compareResponses(a, b Response) bool {
 	return a.Message == b.Message && a.Status == b.Status
 }
@@ -0,0 +1,553 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+// Package pushwooshtest provides an in-process fake of the Pushwoosh API for
+// integration tests, e.g.
+//
+//	server := pushwooshtest.NewServer()
+//	defer server.Close()
+//
+//	client := server.Client()
+//	client.Devices.Register(device)
+//	devices := server.Devices()
+//
+// The server keeps the devices registered, their tags, badges and push
+// stats, and the messages sent. It validates requests like the real API
+// does, answering status code 210 to invalid arguments.
+package pushwooshtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+)
+
+const (
+	// Application is the code of the application every server knows.
+	Application = "TESTS-00000"
+	// AuthToken is the API access token accepted by every server.
+	AuthToken = "testAuthToken"
+)
+
+// Device is a device registered to the server.
+type Device struct {
+	Application string
+	HardwareId  string
+	PushToken   string
+	Type        pushwoosh.DeviceType
+	Language    string
+	TimeZone    int
+	Tags        map[string]interface{}
+	Badge       int
+	// Opened are the hashes of the pushes reported as opened.
+	Opened []string
+}
+
+// Message is a message created through the server, one per notification.
+type Message struct {
+	Code              string
+	Application       string
+	ApplicationsGroup string
+	// DevicesFilter is set for targeted messages only.
+	DevicesFilter string
+	Notification  pushwoosh.Notification
+	Deleted       bool
+}
+
+// Zone is a geozone of an application, as returned by getNearestZone.
+type Zone struct {
+	Name string
+	Lat  float64
+	Lng  float64
+}
+
+// Server is a fake Pushwoosh API. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	apps     map[string]bool
+	devices  map[string]*Device
+	zones    map[string][]Zone
+	messages []Message
+}
+
+// NewServer starts a server knowing the Application application. The
+// caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		apps:    map[string]bool{Application: true},
+		devices: map[string]*Device{},
+		zones:   map[string][]Zone{},
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Client returns a client of the server, for the Application application.
+func (s *Server) Client() *pushwoosh.Client {
+	c := pushwoosh.NewClient(s.Server.Client())
+	u, _ := url.Parse(s.URL + basePath)
+	c.CacheAddrInfo = false
+	c.SetBaseURL(u)
+	c.Application = Application
+	c.AuthToken = AuthToken
+	return c
+}
+
+// AddApplication makes the server know another application.
+func (s *Server) AddApplication(code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apps[code] = true
+}
+
+// AddZone adds a geozone to an application.
+func (s *Server) AddZone(app string, zone Zone) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.zones[app] = append(s.zones[app], zone)
+}
+
+// Devices returns a copy of the registered devices, ordered by application
+// and hardware id.
+func (s *Server) Devices() []Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devices := make([]Device, 0, len(s.devices))
+	for _, d := range s.devices {
+		device := *d
+		device.Tags = map[string]interface{}{}
+		for k, v := range d.Tags {
+			device.Tags[k] = v
+		}
+		device.Opened = append([]string(nil), d.Opened...)
+		devices = append(devices, device)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Application != devices[j].Application {
+			return devices[i].Application < devices[j].Application
+		}
+		return devices[i].HardwareId < devices[j].HardwareId
+	})
+	return devices
+}
+
+// Device returns the device of an application, if registered.
+func (s *Server) Device(app, hwid string) (Device, bool) {
+	for _, d := range s.Devices() {
+		if d.Application == app && d.HardwareId == hwid {
+			return d, true
+		}
+	}
+	return Device{}, false
+}
+
+// SentMessages returns the messages created so far, in order.
+func (s *Server) SentMessages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+var basePath = fmt.Sprintf("/json/%s/", pushwoosh.PushwooshVersion)
+
+// errArgument is an invalid request argument, answered with status 210.
+type errArgument string
+
+func (e errArgument) Error() string {
+	return string(e)
+}
+
+// handler answers a request with the response info, or an error.
+type handler func(s *Server, request json.RawMessage) (interface{}, error)
+
+var handlers = map[string]handler{
+	"registerDevice":        (*Server).registerDevice,
+	"unregisterDevice":      (*Server).unregisterDevice,
+	"setTags":               (*Server).setTags,
+	"setBadge":              (*Server).setBadge,
+	"pushStat":              (*Server).pushStat,
+	"getNearestZone":        (*Server).nearestZone,
+	"createMessage":         (*Server).createMessage,
+	"createTargetedMessage": (*Server).createTargetedMessage,
+	"deleteMessage":         (*Server).deleteMessage,
+	"getMsgStats":           (*Server).messageStats,
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, ok := handlers[strings.TrimPrefix(r.URL.Path, basePath)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var body struct {
+		Request json.RawMessage `json:"request"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Request) <= 0 {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	info, err := h(s, body.Request)
+	s.mu.Unlock()
+
+	resp := struct {
+		Status  int         `json:"status_code"`
+		Message string      `json:"status_message"`
+		Info    interface{} `json:"response,omitempty"`
+	}{200, "OK", info}
+	if err != nil {
+		resp.Status, resp.Message, resp.Info = 210, err.Error(), nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func decode(request json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(request, v); err != nil {
+		return errArgument("Invalid request: " + err.Error())
+	}
+	return nil
+}
+
+func (s *Server) checkApplication(app string) error {
+	if len(app) <= 0 {
+		return errArgument("Application is required")
+	}
+	if !s.apps[app] {
+		return errArgument("Application not found")
+	}
+	return nil
+}
+
+func (s *Server) device(app, hwid string) (*Device, error) {
+	if err := s.checkApplication(app); err != nil {
+		return nil, err
+	}
+	if len(hwid) <= 0 {
+		return nil, errArgument("Hwid is required")
+	}
+	d, ok := s.devices[app+"/"+hwid]
+	if !ok {
+		return nil, errArgument("Device not found")
+	}
+	return d, nil
+}
+
+func (s *Server) registerDevice(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Application string               `json:"application"`
+		PushToken   string               `json:"push_token"`
+		Language    string               `json:"language"`
+		HardwareId  string               `json:"hwid"`
+		TimeZone    int                  `json:"timezone"`
+		Type        pushwoosh.DeviceType `json:"device_type"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	if err := s.checkApplication(r.Application); err != nil {
+		return nil, err
+	}
+	if len(r.HardwareId) <= 0 {
+		return nil, errArgument("Hwid is required")
+	}
+	if len(r.PushToken) <= 0 {
+		return nil, errArgument("Push token is required")
+	}
+	if _, err := strconv.Atoi(r.Type.String()); err == nil {
+		return nil, errArgument("Invalid device type")
+	}
+
+	key := r.Application + "/" + r.HardwareId
+	d, ok := s.devices[key]
+	if !ok {
+		d = &Device{
+			Application: r.Application,
+			HardwareId:  r.HardwareId,
+			Tags:        map[string]interface{}{},
+		}
+		s.devices[key] = d
+	}
+	d.PushToken, d.Type = r.PushToken, r.Type
+	d.Language, d.TimeZone = r.Language, r.TimeZone
+	return nil, nil
+}
+
+func (s *Server) unregisterDevice(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Application string `json:"application"`
+		HardwareId  string `json:"hwid"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	if _, err := s.device(r.Application, r.HardwareId); err != nil {
+		return nil, err
+	}
+	delete(s.devices, r.Application+"/"+r.HardwareId)
+	return nil, nil
+}
+
+func (s *Server) setTags(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Application string                 `json:"application"`
+		HardwareId  string                 `json:"hwid"`
+		Tags        map[string]interface{} `json:"tags"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	d, err := s.device(r.Application, r.HardwareId)
+	if err != nil {
+		return nil, err
+	}
+	if r.Tags == nil {
+		return nil, errArgument("Tags are required")
+	}
+
+	skipped := []pushwoosh.SkippedTag{}
+	for name, value := range r.Tags {
+		switch value.(type) {
+		case nil:
+			delete(d.Tags, name)
+		case string, float64, bool, []interface{}:
+			d.Tags[name] = value
+		default:
+			skipped = append(skipped, pushwoosh.SkippedTag{Tag: name, Reason: "Invalid tag value"})
+		}
+	}
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].Tag < skipped[j].Tag })
+	return map[string]interface{}{"skipped": skipped}, nil
+}
+
+func (s *Server) setBadge(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Application string `json:"application"`
+		HardwareId  string `json:"hwid"`
+		Badge       *int   `json:"badge"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	d, err := s.device(r.Application, r.HardwareId)
+	if err != nil {
+		return nil, err
+	}
+	if r.Badge == nil || *r.Badge < 0 {
+		return nil, errArgument("Badge must be a non negative number")
+	}
+	d.Badge = *r.Badge
+	return nil, nil
+}
+
+func (s *Server) pushStat(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Application string `json:"application"`
+		HardwareId  string `json:"hwid"`
+		Hash        string `json:"hash"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	d, err := s.device(r.Application, r.HardwareId)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Hash) <= 0 {
+		return nil, errArgument("Hash is required")
+	}
+	d.Opened = append(d.Opened, r.Hash)
+	return nil, nil
+}
+
+func (s *Server) nearestZone(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Application string  `json:"application"`
+		HardwareId  string  `json:"hwid"`
+		Lat         float64 `json:"lat"`
+		Lng         float64 `json:"lng"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	if _, err := s.device(r.Application, r.HardwareId); err != nil {
+		return nil, err
+	}
+	if r.Lat < -90 || r.Lat > 90 || r.Lng < -180 || r.Lng > 180 {
+		return nil, errArgument("Invalid coordinates")
+	}
+
+	var nearest *Zone
+	var distance float64
+	for i, zone := range s.zones[r.Application] {
+		if d := haversine(r.Lat, r.Lng, zone.Lat, zone.Lng); nearest == nil || d < distance {
+			nearest, distance = &s.zones[r.Application][i], d
+		}
+	}
+	if nearest == nil {
+		return nil, nil
+	}
+	return map[string]interface{}{
+		"name":     nearest.Name,
+		"lat":      nearest.Lat,
+		"lng":      nearest.Lng,
+		"distance": math.Round(distance),
+	}, nil
+}
+
+// haversine returns the distance in meters between two coordinates.
+func haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadius = 6371000
+	rad := math.Pi / 180
+	dLat, dLng := (lat2-lat1)*rad, (lng2-lng1)*rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func (s *Server) createMessage(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Auth              string                   `json:"auth"`
+		Application       string                   `json:"application"`
+		ApplicationsGroup string                   `json:"applications_group"`
+		Notifications     []pushwoosh.Notification `json:"notifications"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	if r.Auth != AuthToken {
+		return nil, errArgument("Access denied")
+	}
+	if len(r.ApplicationsGroup) <= 0 || len(r.Application) > 0 {
+		if err := s.checkApplication(r.Application); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.Notifications) <= 0 {
+		return nil, errArgument("Notifications are required")
+	}
+	for _, n := range r.Notifications {
+		if err := checkNotification(n); err != nil {
+			return nil, err
+		}
+	}
+
+	codes := []string{}
+	for _, n := range r.Notifications {
+		codes = append(codes, s.addMessage(Message{
+			Application:       r.Application,
+			ApplicationsGroup: r.ApplicationsGroup,
+			Notification:      n,
+		}))
+	}
+	return map[string]interface{}{"Messages": codes}, nil
+}
+
+func (s *Server) createTargetedMessage(request json.RawMessage) (interface{}, error) {
+	var r struct {
+		Auth          string `json:"auth"`
+		DevicesFilter string `json:"devices_filter"`
+		pushwoosh.Notification
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	if r.Auth != AuthToken {
+		return nil, errArgument("Access denied")
+	}
+	if len(r.DevicesFilter) <= 0 {
+		return nil, errArgument("Devices filter is required")
+	}
+	if _, err := pushwoosh.ParseFilter(r.DevicesFilter); err != nil {
+		return nil, errArgument("Invalid devices filter: " + err.Error())
+	}
+	if err := checkNotification(r.Notification); err != nil {
+		return nil, err
+	}
+
+	code := s.addMessage(Message{DevicesFilter: r.DevicesFilter, Notification: r.Notification})
+	return map[string]interface{}{"messageCode": code}, nil
+}
+
+func (s *Server) message(request json.RawMessage) (*Message, error) {
+	var r struct {
+		Auth    string `json:"auth"`
+		Message string `json:"message"`
+	}
+	if err := decode(request, &r); err != nil {
+		return nil, err
+	}
+	if r.Auth != AuthToken {
+		return nil, errArgument("Access denied")
+	}
+	for i := range s.messages {
+		if s.messages[i].Code == r.Message {
+			return &s.messages[i], nil
+		}
+	}
+	return nil, errArgument("Message not found")
+}
+
+func (s *Server) deleteMessage(request json.RawMessage) (interface{}, error) {
+	m, err := s.message(request)
+	if err != nil {
+		return nil, err
+	}
+	m.Deleted = true
+	return nil, nil
+}
+
+// messageStats answers no statistics, as the server delivers nothing.
+func (s *Server) messageStats(request json.RawMessage) (interface{}, error) {
+	if _, err := s.message(request); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"rows": []pushwoosh.CampaignStat{}}, nil
+}
+
+func checkNotification(n pushwoosh.Notification) error {
+	if len(n.Content) <= 0 && len(n.Preset) <= 0 {
+		return errArgument("Content is required")
+	}
+	if n.SendDate != "now" {
+		if _, err := time.Parse("2006-01-02 15:04", n.SendDate); err != nil {
+			return errArgument("Invalid send date")
+		}
+	}
+	for _, t := range n.Platforms {
+		if _, err := strconv.Atoi(t.String()); err == nil {
+			return errArgument("Invalid platform")
+		}
+	}
+	if n.InboxDate != "" {
+		if _, err := time.Parse("2006-01-02", n.InboxDate); err != nil {
+			return errArgument("Invalid inbox date")
+		}
+	}
+	return nil
+}
+
+func (s *Server) addMessage(m Message) string {
+	m.Code = fmt.Sprintf("FAKE-%08X", len(s.messages)+1)
+	s.messages = append(s.messages, m)
+	return m.Code
+}
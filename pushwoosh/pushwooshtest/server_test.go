@@ -0,0 +1,173 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwooshtest
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+)
+
+type taggedDevice struct {
+	pushwoosh.Device
+	Country string      `tag:"Country"`
+	Cart    float64     `tag:"cart_value"`
+	Extra   interface{} `tag:"extra"`
+}
+
+func TestServer_devices(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	device := pushwoosh.Device{
+		HardwareId: "testHardwareId",
+		Language:   "en",
+		PushToken:  "testPushToken",
+		Type:       pushwoosh.IOS,
+	}
+	if _, err := client.Devices.Register(device); err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	tagged := taggedDevice{device, "es", 50, map[string]int{"a": 1}}
+	resp, err := client.Devices.SetTags(tagged)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if want := []pushwoosh.SkippedTag{{Tag: "extra", Reason: "Invalid tag value"}}; !reflect.DeepEqual(resp.Info.Skipped, want) {
+		t.Errorf("Skipped = %v, want %v", resp.Info.Skipped, want)
+	}
+	if _, err := client.Devices.SetBadge(device, 3); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if _, err := client.Devices.PushStat(device, "testHash"); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+
+	want := []Device{{
+		Application: Application,
+		HardwareId:  "testHardwareId",
+		PushToken:   "testPushToken",
+		Type:        pushwoosh.IOS,
+		Language:    "en",
+		Tags:        map[string]interface{}{"Country": "es", "cart_value": 50.0},
+		Badge:       3,
+		Opened:      []string{"testHash"},
+	}}
+	if devices := server.Devices(); !reflect.DeepEqual(devices, want) {
+		t.Errorf("Devices = %+v, want %+v", devices, want)
+	}
+
+	if _, err := client.Devices.Unregister("testHardwareId"); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if _, ok := server.Device(Application, "testHardwareId"); ok {
+		t.Errorf("Expected the device to be unregistered")
+	}
+}
+
+func TestServer_validation(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	device := pushwoosh.Device{HardwareId: "testHardwareId", PushToken: "testPushToken", Type: pushwoosh.IOS}
+
+	_, err := client.App("OTHER-00000").Devices.Register(device)
+	if e, ok := err.(pushwoosh.ErrorResponse); !ok || e.Status != 210 || e.Message != "Application not found" {
+		t.Errorf("Error = %v, want an application not found error", err)
+	}
+	_, err = client.Devices.SetBadge(device, 1)
+	if e, ok := err.(pushwoosh.ErrorResponse); !ok || e.Status != 210 || e.Message != "Device not found" {
+		t.Errorf("Error = %v, want a device not found error", err)
+	}
+	device.Type = 99
+	if _, err = client.Devices.Register(device); err == nil {
+		t.Errorf("Expected an error")
+	}
+
+	client.AuthToken = "otherAuthToken"
+	_, err = client.Messages.Create(pushwoosh.Notification{Content: pushwoosh.Text("Hello")})
+	if e, ok := err.(pushwoosh.ErrorResponse); !ok || e.Message != "Access denied" {
+		t.Errorf("Error = %v, want an access denied error", err)
+	}
+
+	resp, err := http.Get(server.URL + basePath + "registerDevice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServer_messages(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	created, err := client.Messages.Create(
+		pushwoosh.Notification{Content: pushwoosh.Text("Hello")},
+		pushwoosh.Notification{Content: pushwoosh.Localized{"en": "Hi", "es": "Hola"}, SendDate: "2030-01-02 15:04"},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	filter := pushwoosh.And(pushwoosh.A(Application), pushwoosh.T("Country", pushwoosh.EQ, "es"))
+	targeted, err := client.Messages.CreateTargeted(filter, pushwoosh.Notification{Preset: "testPreset"})
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if _, err := client.Messages.Create(pushwoosh.Notification{Content: pushwoosh.Text("Hi"), SendDate: "tomorrow"}); err == nil {
+		t.Errorf("Expected an error")
+	}
+
+	messages := server.SentMessages()
+	if len(messages) != 3 {
+		t.Fatalf("Messages = %d, want %d", len(messages), 3)
+	}
+	codes := []string{messages[0].Code, messages[1].Code}
+	if !reflect.DeepEqual(created.Info.Messages, codes) {
+		t.Errorf("Messages = %v, want %v", created.Info.Messages, codes)
+	}
+	if messages[1].Notification.Content["es"] != "Hola" || messages[1].Application != Application {
+		t.Errorf("Message = %+v, want the localized notification", messages[1])
+	}
+	if messages[2].Code != targeted.Info.MessageCode || messages[2].DevicesFilter != filter.String() {
+		t.Errorf("Message = %+v, want the targeted notification", messages[2])
+	}
+
+	if _, err := client.Messages.Delete(messages[0].Code); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !server.SentMessages()[0].Deleted {
+		t.Errorf("Expected the message to be deleted")
+	}
+	if _, err := client.Messages.Stats("missing"); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestServer_nearestZone(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	server.AddZone(Application, Zone{"Madrid", 40.4168, -3.7038})
+	server.AddZone(Application, Zone{"Barcelona", 41.3874, 2.1686})
+	device := pushwoosh.Device{HardwareId: "testHardwareId", PushToken: "testPushToken", Type: pushwoosh.Android}
+	client.Devices.Register(device)
+
+	resp, err := client.Devices.NearestZone(device, 41.4, 2.17)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.Name != "Barcelona" || resp.Info.Distance <= 0 || resp.Info.Distance > 2000 {
+		t.Errorf("Zone = %+v, want Barcelona within 2 km", resp.Info)
+	}
+}
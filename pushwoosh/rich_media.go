@@ -0,0 +1,97 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+// RichMedia is a page shown when a notification is opened, referenced by
+// its code from Notification.RichMedia.
+type RichMedia struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Created string `json:"created,omitempty"`
+}
+
+type RichMediaResponse struct {
+	Response
+	Info struct {
+		Code string `json:"code,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type RichMediaListResponse struct {
+	Response
+	Info struct {
+		RichMedia []RichMedia `json:"rich_media,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type RichMediaService struct {
+	client *Client
+}
+
+// Upload stores a rich media ZIP bundle, as built by PackRichMediaBundle.
+// The code of the new rich media is returned in the response.
+func (s RichMediaService) Upload(name string, bundle []byte) (*RichMediaResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(name) <= 0 {
+		return nil, errors.New("Rich media name is required")
+	}
+	if len(bundle) <= 0 {
+		return nil, errors.New("Rich media bundle is required")
+	}
+	if len(bundle) > MaxRichMediaBundleSize {
+		return nil, errors.New("Rich media bundle is too large")
+	}
+	body := struct {
+		Application string `json:"application"`
+		Name        string `json:"name"`
+		Bundle      []byte `json:"zip"`
+	}{s.client.Application, name, bundle}
+	req, err := s.client.NewAuthRequest("POST", "/uploadRichMedia", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(RichMediaResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s RichMediaService) List() (*RichMediaListResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+	}{s.client.Application}
+	req, err := s.client.NewAuthRequest("POST", "/listRichMedia", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(RichMediaListResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s RichMediaService) Delete(code string) (*Response, error) {
+	if len(code) <= 0 {
+		return nil, errors.New("Rich media code is required")
+	}
+	body := struct {
+		Code string `json:"code"`
+	}{code}
+	req, err := s.client.NewAuthRequest("POST", "/deleteRichMedia", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxRichMediaBundleSize is the largest ZIP bundle accepted by
+	// RichMediaService.Upload.
+	MaxRichMediaBundleSize = 10 << 20
+	// MaxRichMediaFileSize is the largest file accepted in a bundle.
+	MaxRichMediaFileSize = 5 << 20
+)
+
+// RichMediaBundleError lists every problem found in a rich media bundle.
+type RichMediaBundleError []string
+
+func (e RichMediaBundleError) Error() string {
+	return "Invalid rich media bundle: " + strings.Join(e, "; ")
+}
+
+var richMediaReference = regexp.MustCompile(`(?i)(?:\b(?:src|href)\s*=\s*["']([^"']*)["']|url\(\s*["']?([^"')]*)["']?\s*\))`)
+
+// ValidateRichMediaBundle checks a rich media bundle directory before it is
+// packed: index.html must be at its root, files must be within size limits
+// and every local asset referenced from HTML and CSS files must exist in the
+// bundle. Problems are reported together as a RichMediaBundleError.
+func ValidateRichMediaBundle(dir string) error {
+	files, err := richMediaFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	var problems RichMediaBundleError
+	if _, ok := files["index.html"]; !ok {
+		problems = append(problems, "index.html is missing")
+	}
+	var total int64
+	names := make([]string, 0, len(files))
+	for name, size := range files {
+		names = append(names, name)
+		total += size
+	}
+	sort.Strings(names)
+	if total > MaxRichMediaBundleSize {
+		problems = append(problems, fmt.Sprintf("bundle size %d exceeds %d bytes", total, MaxRichMediaBundleSize))
+	}
+	for _, name := range names {
+		if files[name] > MaxRichMediaFileSize {
+			problems = append(problems, fmt.Sprintf("%s size %d exceeds %d bytes", name, files[name], MaxRichMediaFileSize))
+		}
+		ext := strings.ToLower(path.Ext(name))
+		if ext != ".html" && ext != ".htm" && ext != ".css" {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		for _, ref := range richMediaReferences(content) {
+			target, ok := resolveRichMediaReference(name, ref)
+			if !ok {
+				continue
+			}
+			if _, found := files[target]; !found {
+				problems = append(problems, fmt.Sprintf("%s references missing asset %s", name, ref))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// PackRichMediaBundle validates a rich media bundle directory and returns
+// it as a ZIP archive, ready for RichMediaService.Upload.
+func PackRichMediaBundle(dir string) ([]byte, error) {
+	if err := ValidateRichMediaBundle(dir); err != nil {
+		return nil, err
+	}
+	files, err := richMediaFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buffer := new(bytes.Buffer)
+	archive := zip.NewWriter(buffer)
+	for _, name := range names {
+		w, err := archive.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(w, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	if buffer.Len() > MaxRichMediaBundleSize {
+		return nil, RichMediaBundleError{fmt.Sprintf("archive size %d exceeds %d bytes", buffer.Len(), MaxRichMediaBundleSize)}
+	}
+	return buffer.Bytes(), nil
+}
+
+// richMediaFiles returns the size of every regular file of the bundle,
+// keyed by its slash separated path relative to dir.
+func richMediaFiles(dir string) (map[string]int64, error) {
+	files := map[string]int64{}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info.Size()
+		return nil
+	})
+	return files, err
+}
+
+func richMediaReferences(content []byte) []string {
+	var refs []string
+	for _, m := range richMediaReference.FindAllSubmatch(content, -1) {
+		ref := string(m[1])
+		if len(ref) <= 0 {
+			ref = string(m[2])
+		}
+		ref = strings.TrimSpace(ref)
+		if len(ref) > 0 {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// resolveRichMediaReference returns the bundle path a reference found in
+// file from points to. It reports false for references outside the bundle,
+// such as absolute URLs or fragments.
+func resolveRichMediaReference(from, ref string) (string, bool) {
+	u, err := url.Parse(ref)
+	if err != nil || len(u.Scheme) > 0 || len(u.Host) > 0 || len(u.Path) <= 0 {
+		return "", false
+	}
+	if strings.HasPrefix(u.Path, "/") {
+		return strings.TrimPrefix(path.Clean(u.Path), "/"), true
+	}
+	return path.Join(path.Dir(from), u.Path), true
+}
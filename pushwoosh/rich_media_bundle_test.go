@@ -0,0 +1,108 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestValidateRichMediaBundle(t *testing.T) {
+	dir := richMediaBundleTest(t, map[string]string{
+		"index.html": `<html><link href="css/style.css"><img src='img/logo.png'>` +
+			`<a href="https://example.com">x</a><a href="#top">y</a><img src="data:image/png;base64,AA"></html>`,
+		"css/style.css": `body { background: url("../img/bg.png") }`,
+		"img/logo.png":  "png",
+		"img/bg.png":    "png",
+	})
+	defer os.RemoveAll(dir)
+
+	if err := ValidateRichMediaBundle(dir); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestValidateRichMediaBundle_invalid(t *testing.T) {
+	dir := richMediaBundleTest(t, map[string]string{
+		"page.html":     `<img src="missing.png"><img src="../outside.png">`,
+		"css/style.css": `a { background: url(/img/missing.png) }`,
+	})
+	defer os.RemoveAll(dir)
+
+	err := ValidateRichMediaBundle(dir)
+	problems, ok := err.(RichMediaBundleError)
+	if !ok {
+		t.Fatalf("Error = %v, want a RichMediaBundleError", err)
+	}
+	sort.Strings(problems)
+	want := RichMediaBundleError{
+		"css/style.css references missing asset /img/missing.png",
+		"index.html is missing",
+		"page.html references missing asset ../outside.png",
+		"page.html references missing asset missing.png",
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Problems = %q, want %q", problems, want)
+	}
+}
+
+func TestValidateRichMediaBundle_tooLarge(t *testing.T) {
+	dir := richMediaBundleTest(t, map[string]string{
+		"index.html": "<html></html>",
+		"video.mp4":  string(make([]byte, MaxRichMediaFileSize+1)),
+	})
+	defer os.RemoveAll(dir)
+
+	if err := ValidateRichMediaBundle(dir); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestPackRichMediaBundle(t *testing.T) {
+	dir := richMediaBundleTest(t, map[string]string{
+		"index.html":   `<img src="img/logo.png">`,
+		"img/logo.png": "png",
+	})
+	defer os.RemoveAll(dir)
+
+	bundle, err := PackRichMediaBundle(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	archive, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		t.Fatalf("Expected no error, found %s", err.Error())
+	}
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	want := []string{"img/logo.png", "index.html"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Files = %v, want %v", names, want)
+	}
+}
+
+func richMediaBundleTest(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "pushwoosh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0700)
+		if err := ioutil.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRichMediaService_Upload(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	bundle := []byte("PK\x03\x04 bundle")
+	mux.HandleFunc("/uploadRichMedia", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Auth        string `json:"auth"`
+				Application string `json:"application"`
+				Name        string `json:"name"`
+				Bundle      []byte `json:"zip"`
+			} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		if body.Request.Auth != "testAuthToken" {
+			t.Errorf("Auth = %s, want %s", body.Request.Auth, "testAuthToken")
+		}
+		if body.Request.Name != "welcome" {
+			t.Errorf("Name = %s, want %s", body.Request.Name, "welcome")
+		}
+		if !bytes.Equal(body.Request.Bundle, bundle) {
+			t.Errorf("Bundle = %q, want %q", body.Request.Bundle, bundle)
+		}
+
+		var res RichMediaResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Code = "testRichMedia"
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.RichMedia.Upload("welcome", bundle)
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if resp.Info.Code != "testRichMedia" {
+		t.Errorf("Code = %s, want %s", resp.Info.Code, "testRichMedia")
+	}
+}
+
+func TestRichMediaService_Upload_invalidBundle(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	if _, err := client.RichMedia.Upload("welcome", nil); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if _, err := client.RichMedia.Upload("welcome", make([]byte, MaxRichMediaBundleSize+1)); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestRichMediaService_List(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	richMedia := []RichMedia{{Code: "testRichMedia", Name: "welcome"}}
+	mux.HandleFunc("/listRichMedia", func(w http.ResponseWriter, r *http.Request) {
+		var res RichMediaListResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.RichMedia = richMedia
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.RichMedia.List()
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !reflect.DeepEqual(resp.Info.RichMedia, richMedia) {
+		t.Errorf("RichMedia = %v, want %v", resp.Info.RichMedia, richMedia)
+	}
+}
+
+func TestRichMediaService_Delete(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/deleteRichMedia", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Code string `json:"code"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Request.Code != "testRichMedia" {
+			t.Errorf("Code = %s, want %s", body.Request.Code, "testRichMedia")
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.AuthToken = "testAuthToken"
+	if _, err := client.RichMedia.Delete("testRichMedia"); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
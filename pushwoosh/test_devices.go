@@ -0,0 +1,95 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"errors"
+)
+
+// TestDevice is a device receiving the messages sent with
+// MessagesService.SendToTestDevices.
+type TestDevice struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	PushToken   string     `json:"push_token"`
+	Type        DeviceType `json:"device_type"`
+}
+
+type TestDevicesResponse struct {
+	Response
+	Info struct {
+		TestDevices []TestDevice `json:"TestDevices,omitempty"`
+	} `json:"response,omitempty"`
+}
+
+type TestDevicesService struct {
+	client *Client
+}
+
+// Create marks a registered device as a test device.
+func (s TestDevicesService) Create(device Registrable, name, description string) (*Response, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if err := checkDevice(device); err != nil {
+		return nil, err
+	}
+	if len(name) <= 0 {
+		return nil, errors.New("Test device name is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+		TestDevice
+	}{s.client.Application, TestDevice{name, description, device.DevicePushToken(), device.DeviceType()}}
+	req, err := s.client.NewAuthRequest("POST", "/createTestDevice", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+func (s TestDevicesService) List() (*TestDevicesResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	body := struct {
+		Application string `json:"application"`
+	}{s.client.Application}
+	req, err := s.client.NewAuthRequest("POST", "/listTestDevices", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(TestDevicesResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
+
+// SendToTestDevices sends the notification to the test devices of the
+// application only.
+func (s MessagesService) SendToTestDevices(notification Notification) (*MessagesResponse, error) {
+	if len(s.client.Application) <= 0 {
+		return nil, errors.New("Application token is required")
+	}
+	if len(notification.Devices) > 0 || len(notification.Filter) > 0 {
+		return nil, errors.New("Test messages are addressed to test devices only")
+	}
+	if err := checkNotification(&notification); err != nil {
+		return nil, err
+	}
+	body := struct {
+		Application   string         `json:"application"`
+		Notifications []Notification `json:"notifications"`
+	}{s.client.Application, []Notification{notification}}
+	req, err := s.client.NewAuthRequest("POST", "/createTestMessage", body)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(MessagesResponse)
+	err = s.client.Do(req, resp)
+	return resp, err
+}
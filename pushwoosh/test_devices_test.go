@@ -0,0 +1,138 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package pushwoosh
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestTestDevicesService_Create(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createTestDevice", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request map[string]interface{} `json:"request"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Errorf("Expected no error, found %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"auth":        "testAuthToken",
+			"application": "testAppToken",
+			"name":        "QA iPhone",
+			"description": "Alice",
+			"push_token":  "testPushToken",
+			"device_type": 1.0,
+		}
+		if !reflect.DeepEqual(body.Request, want) {
+			t.Errorf("Request = %v, want %v", body.Request, want)
+		}
+		json.NewEncoder(w).Encode(Response{Status: 200, Message: "OK"})
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	device := Device{
+		HardwareId: "testHardwareId",
+		PushToken:  "testPushToken",
+		Type:       IOS,
+	}
+	_, err := client.TestDevices.Create(device, "QA iPhone", "Alice")
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+}
+
+func TestTestDevicesService_Create_invalidDevice(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	device := Device{HardwareId: "testHardwareId", Type: IOS}
+	_, err := client.TestDevices.Create(device, "QA iPhone", "")
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestTestDevicesService_List(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	devices := []TestDevice{{Name: "QA iPhone", PushToken: "testPushToken", Type: IOS}}
+	mux.HandleFunc("/listTestDevices", func(w http.ResponseWriter, r *http.Request) {
+		var res TestDevicesResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.TestDevices = devices
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.TestDevices.List()
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !reflect.DeepEqual(resp.Info.TestDevices, devices) {
+		t.Errorf("TestDevices = %v, want %v", resp.Info.TestDevices, devices)
+	}
+}
+
+func TestMessagesService_SendToTestDevices(t *testing.T) {
+	mux, server, client := sandbox()
+	defer server.Close()
+
+	mux.HandleFunc("/createTestMessage", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Request struct {
+				Application   string         `json:"application"`
+				Notifications []Notification `json:"notifications"`
+			} `json:"request"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		want := []Notification{{SendDate: "now", Content: Text("Pre-release")}}
+		if !reflect.DeepEqual(body.Request.Notifications, want) {
+			t.Errorf("Notifications = %v, want %v", body.Request.Notifications, want)
+		}
+
+		var res MessagesResponse
+		res.Status = 200
+		res.Message = "OK"
+		res.Info.Messages = []string{"testMessageCode"}
+		json.NewEncoder(w).Encode(res)
+	})
+
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	resp, err := client.Messages.SendToTestDevices(Notification{Content: Text("Pre-release")})
+	if err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if !reflect.DeepEqual(resp.Info.Messages, []string{"testMessageCode"}) {
+		t.Errorf("Messages = %v, want %v", resp.Info.Messages, []string{"testMessageCode"})
+	}
+}
+
+func TestMessagesService_SendToTestDevices_invalidDevices(t *testing.T) {
+	client := NewClient(nil)
+	client.Application = "testAppToken"
+	client.AuthToken = "testAuthToken"
+	_, err := client.Messages.SendToTestDevices(Notification{
+		Content: Text("Pre-release"),
+		Devices: []string{"hwid1"},
+	})
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
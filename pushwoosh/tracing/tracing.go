@@ -0,0 +1,134 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+// Package tracing creates OpenTelemetry spans for the requests made by a
+// pushwoosh.Client. It lives apart from the pushwoosh package so the client
+// does not depend on OpenTelemetry.
+//
+// Spans are children of the span in the request context, which is set with
+// Client.WithContext, and the trace context is propagated to Pushwoosh in
+// the request headers:
+//
+//	client.Observe(tracing.New(nil, nil))
+//	client.WithContext(ctx).Devices.SetTags(device)
+//
+// Like every observer, only API requests are traced: export file downloads
+// and arguments rejected by the client create no span.
+package tracing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/stelapps/go-pushwoosh/pushwoosh/tracing"
+
+// Span attributes, besides the HTTP response status code.
+const (
+	ApplicationKey = attribute.Key("pushwoosh.application")
+	DeviceTypeKey  = attribute.Key("pushwoosh.device_type")
+	StatusCodeKey  = attribute.Key("pushwoosh.status_code")
+	SkippedTagsKey = attribute.Key("pushwoosh.skipped_tags")
+)
+
+// Tracer is a pushwoosh.Observer creating a client span per request, named
+// after its endpoint, e.g. "/setTags".
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	spans      sync.Map
+}
+
+// New returns a Tracer using the given provider and propagator, or the
+// global ones when nil.
+func New(provider trace.TracerProvider, propagator propagation.TextMapPropagator) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	return &Tracer{
+		tracer:     provider.Tracer(instrumentationName),
+		propagator: propagator,
+	}
+}
+
+func (t *Tracer) RequestStarted(req *http.Request, endpoint string) {
+	ctx, span := t.tracer.Start(req.Context(), endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(requestAttributes(req)...),
+	)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	t.spans.Store(req, span)
+}
+
+func (t *Tracer) RequestFinished(req *http.Request, info pushwoosh.RequestInfo) {
+	v, ok := t.spans.Load(req)
+	if !ok {
+		return
+	}
+	t.spans.Delete(req)
+	span := v.(trace.Span)
+	defer span.End()
+
+	if info.HTTPStatus != 0 {
+		span.SetAttributes(attribute.Int("http.status_code", info.HTTPStatus))
+	}
+	if info.APIStatus != 0 {
+		span.SetAttributes(StatusCodeKey.Int(info.APIStatus))
+	}
+	if tags, ok := info.Result.(*pushwoosh.TagsResponse); ok && info.HTTPStatus == 200 {
+		span.SetAttributes(SkippedTagsKey.Int(len(tags.Info.Skipped)))
+	}
+	if info.Err != nil {
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, string(info.Outcome()))
+	}
+}
+
+// requestAttributes returns the application code and device type of the
+// request body, when present.
+func requestAttributes(req *http.Request) []attribute.KeyValue {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+
+	var v struct {
+		Request struct {
+			Application string `json:"application"`
+			DeviceType  int    `json:"device_type"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	if v.Request.Application != "" {
+		attrs = append(attrs, ApplicationKey.String(v.Request.Application))
+	}
+	if v.Request.DeviceType != 0 {
+		attrs = append(attrs, DeviceTypeKey.String(pushwoosh.DeviceType(v.Request.DeviceType).String()))
+	}
+	return attrs
+}
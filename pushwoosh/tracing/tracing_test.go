@@ -0,0 +1,109 @@
+// Copyright (c) 2013, Álvaro Vilanova Vidal
+// Copyright (c) 2013, Stelapps (Appsales Dev S.L.)
+// Use of this source code is governed by a BSD 2-Clause
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stelapps/go-pushwoosh/pushwoosh"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/setTags", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("traceparent") == "" {
+			t.Errorf("Expected the trace context to be propagated")
+		}
+		var resp pushwoosh.TagsResponse
+		resp.Status, resp.Message = 200, "OK"
+		resp.Info.Skipped = []pushwoosh.SkippedTag{{Tag: "foo", Reason: "bar"}}
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/registerDevice", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pushwoosh.Response{Status: 210, Message: "foo"})
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := tracingClient(server)
+	client.Observe(New(provider, propagation.TraceContext{}))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	device := pushwoosh.Device{HardwareId: "testHardwareId", PushToken: "testPushToken", Type: pushwoosh.IOS}
+	if _, err := client.WithContext(ctx).Devices.SetTags(device); err != nil {
+		t.Errorf("Expected no error, found %s", err.Error())
+	}
+	if _, err := client.Devices.Register(device); err == nil {
+		t.Errorf("Expected an error")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Spans = %d, want %d", len(spans), 3)
+	}
+
+	tags := spans[0]
+	if tags.Name() != "/setTags" || tags.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Span = %s %s, want /setTags client", tags.Name(), tags.SpanKind())
+	}
+	if tags.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected the span to be a child of the context span")
+	}
+	wantAttributes(t, tags.Attributes(), map[attribute.Key]attribute.Value{
+		ApplicationKey:     attribute.StringValue("testAppToken"),
+		"http.status_code": attribute.IntValue(200),
+		StatusCodeKey:      attribute.IntValue(200),
+		SkippedTagsKey:     attribute.IntValue(1),
+	})
+
+	register := spans[1]
+	if register.Name() != "/registerDevice" || register.Parent().IsValid() {
+		t.Errorf("Span = %s, want a /registerDevice root span", register.Name())
+	}
+	wantAttributes(t, register.Attributes(), map[attribute.Key]attribute.Value{
+		DeviceTypeKey: attribute.StringValue(pushwoosh.IOS.String()),
+		StatusCodeKey: attribute.IntValue(210),
+	})
+	if register.Status().Code != codes.Error {
+		t.Errorf("Status = %v, want %v", register.Status().Code, codes.Error)
+	}
+}
+
+func wantAttributes(t *testing.T, attrs []attribute.KeyValue, want map[attribute.Key]attribute.Value) {
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range attrs {
+		got[kv.Key] = kv.Value
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k].Emit(), v.Emit())
+		}
+	}
+}
+
+func tracingClient(server *httptest.Server) *pushwoosh.Client {
+	client := pushwoosh.NewClient(nil)
+	u, _ := url.Parse(server.URL)
+	client.CacheAddrInfo = false
+	client.SetBaseURL(u)
+	client.Application = "testAppToken"
+	return client
+}